@@ -0,0 +1,118 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	connectLong = templates.LongDesc(`
+		Open a shell of the database client inside a pod of a database.
+		The primary pod is used when the pods of the database have roles, otherwise the first ready pod.
+		The credentials are read from the auth secret of the database and the client is configured
+		with the client certificates of the database when TLS is enabled.
+
+		The clients are psql for Postgres, mysql for MySQL, MariaDB and PerconaXtraDB, mongo for MongoDB
+		and redis-cli for Redis. For Elasticsearch, the cluster information is requested with curl.
+    `)
+
+	connectExample = templates.Examples(`
+		# Open a psql shell in the primary pod of a postgres
+		kubectl dba connect pg postgres-demo
+
+		# Open a mongo shell in the primary pod of a mongodb in the demo namespace
+		kubectl dba connect mg/mongo-demo -n demo
+`)
+)
+
+type ConnectOptions struct {
+	Namespace   string
+	BuilderArgs []string
+
+	config *rest.Config
+	client kubernetes.Interface
+	f      cmdutil.Factory
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdConnect(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ConnectOptions{
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "connect (TYPE NAME | TYPE/NAME)",
+		Short:   i18n.T("Open a shell of the database client in a database pod"),
+		Long:    connectLong,
+		Example: connectExample,
+		Args:    cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+
+	return cmd
+}
+
+func (o *ConnectOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.BuilderArgs = args
+	o.f = f
+
+	o.config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *ConnectOptions) Run() error {
+	db, err := getDatabase(o.f, o.Namespace, o.BuilderArgs)
+	if err != nil {
+		return err
+	}
+	creds, err := db.getCredentials(o.client)
+	if err != nil {
+		return err
+	}
+	command, interactive, err := db.clientCommand(creds, clientOptions{})
+	if err != nil {
+		return err
+	}
+	pod, err := db.getPrimaryPod(o.client)
+	if err != nil {
+		return err
+	}
+	return execInPod(o.config, o.client, pod, db.container, command, o.IOStreams, interactive, interactive)
+}
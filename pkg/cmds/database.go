@@ -0,0 +1,540 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/term"
+	core_util "kmodules.xyz/client-go/core/v1"
+)
+
+// Directories where the KubeDB operator mounts the client certificates in the
+// database containers when TLS is configured. The apimachinery only defines
+// them for MongoDB and Elasticsearch, see api.MongoCertDirectory and
+// api.ElasticsearchConfigDir.
+const (
+	postgresCertDir = "/tls/certs/client"
+	mysqlCertDir    = "/etc/mysql/certs"
+	redisCertDir    = "/certs"
+)
+
+// Names of the client certificate and key in the certificate directories. The
+// CA certificate is named api.TLSCACertFileName.
+const (
+	clientCertFile = "client.crt"
+	clientKeyFile  = "client.key"
+)
+
+// Environment variables of the database containers that the KubeDB operator
+// sets from the auth secret. The clients read the password from them inside
+// the container, so that it is not part of the exec request. The MySQL
+// variable is api.MySQLRootPassword, and redis-cli reads REDISCLI_AUTH itself.
+const (
+	postgresPasswordEnv      = "POSTGRES_PASSWORD"
+	mongoPasswordEnv         = "MONGO_INITDB_ROOT_PASSWORD"
+	elasticsearchUserEnv     = "ELASTIC_USER"
+	elasticsearchPasswordEnv = "ELASTIC_PASSWORD"
+)
+
+// database is a KubeDB database given on the command line.
+type database struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// resource is the API resource of the database.
+	resource schema.GroupVersionResource
+	// object is the typed database object.
+	object runtime.Object
+	// selector selects the pods that serve the clients of the database.
+	selector map[string]string
+	// container is the name of the database container in the pods.
+	container string
+	// authSecret is the name of the secret with the credentials of the
+	// database, or empty if it does not have one.
+	authSecret string
+}
+
+// credentials are the credentials read from the auth secret of a database.
+type credentials struct {
+	Username string
+	Password string
+}
+
+// getDatabase returns the database given as TYPE NAME or TYPE/NAME in args.
+func getDatabase(f cmdutil.Factory, namespace string, args []string) (*database, error) {
+	infos, err := f.NewBuilder().
+		Unstructured().
+		NamespaceParam(namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(false, args...).
+		SingleResourceType().
+		Flatten().
+		Do().
+		Infos()
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) != 1 {
+		return nil, fmt.Errorf("expected a single database, found %d", len(infos))
+	}
+	u, ok := infos[0].Object.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", infos[0].Object)
+	}
+	d, err := newDatabase(u)
+	if err != nil {
+		return nil, err
+	}
+	d.resource = infos[0].Mapping.Resource
+	return d, nil
+}
+
+func newDatabase(u *unstructured.Unstructured) (*database, error) {
+	d := &database{
+		Kind:      u.GetKind(),
+		Namespace: u.GetNamespace(),
+		Name:      u.GetName(),
+	}
+	if u.GroupVersionKind().Group != api.SchemeGroupVersion.Group {
+		return nil, fmt.Errorf("%s %s/%s is not a KubeDB database", d.Kind, d.Namespace, d.Name)
+	}
+
+	switch d.Kind {
+	case api.ResourceKindElasticsearch:
+		d.object, d.container = &api.Elasticsearch{}, api.ResourceSingularElasticsearch
+	case api.ResourceKindEtcd:
+		d.object, d.container = &api.Etcd{}, api.ResourceSingularEtcd
+	case api.ResourceKindMariaDB:
+		d.object, d.container = &api.MariaDB{}, api.ResourceSingularMariaDB
+	case api.ResourceKindMemcached:
+		d.object, d.container = &api.Memcached{}, api.ResourceSingularMemcached
+	case api.ResourceKindMongoDB:
+		d.object, d.container = &api.MongoDB{}, api.ResourceSingularMongoDB
+	case api.ResourceKindMySQL:
+		d.object, d.container = &api.MySQL{}, api.ResourceSingularMySQL
+	case api.ResourceKindPerconaXtraDB:
+		d.object, d.container = &api.PerconaXtraDB{}, api.ResourceSingularPerconaXtraDB
+	case api.ResourceKindPgBouncer:
+		d.object, d.container = &api.PgBouncer{}, api.ResourceSingularPgBouncer
+	case api.ResourceKindPostgres:
+		d.object, d.container = &api.Postgres{}, api.ResourceSingularPostgres
+	case api.ResourceKindProxySQL:
+		d.object, d.container = &api.ProxySQL{}, api.ResourceSingularProxySQL
+	case api.ResourceKindRedis:
+		d.object, d.container = &api.Redis{}, api.ResourceSingularRedis
+	default:
+		return nil, fmt.Errorf("%s %s/%s is not a KubeDB database", d.Kind, d.Namespace, d.Name)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), d.object); err != nil {
+		return nil, err
+	}
+
+	switch db := d.object.(type) {
+	case *api.Elasticsearch:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	case *api.Etcd:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	case *api.MariaDB:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	case *api.Memcached:
+		d.selector = db.OffshootSelectors()
+	case *api.MongoDB:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+		if db.Spec.ShardTopology != nil {
+			// clients of a sharded cluster connect to the mongos routers
+			d.selector = db.MongosSelectors()
+		}
+	case *api.MySQL:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	case *api.PerconaXtraDB:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	case *api.PgBouncer:
+		d.selector = db.OffshootSelectors()
+	case *api.Postgres:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	case *api.ProxySQL:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	case *api.Redis:
+		d.selector, d.authSecret = db.OffshootSelectors(), secretName(db.Spec.AuthSecret)
+	}
+	return d, nil
+}
+
+func secretName(ref *core.LocalObjectReference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+// getCredentials reads the username and password from the auth secret of
+// the database. It returns empty credentials if the database does not have
+// an auth secret.
+func (d *database) getCredentials(client kubernetes.Interface) (*credentials, error) {
+	if d.authSecret == "" {
+		return &credentials{}, nil
+	}
+	secret, err := client.CoreV1().Secrets(d.Namespace).Get(context.TODO(), d.authSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the auth secret of %s %s/%s: %v", d.Kind, d.Namespace, d.Name, err)
+	}
+	return &credentials{
+		Username: string(secret.Data[core.BasicAuthUsernameKey]),
+		Password: string(secret.Data[core.BasicAuthPasswordKey]),
+	}, nil
+}
+
+// getPrimaryPod returns the pod that clients connect to: the running pod
+// labelled as primary if the database has one, otherwise the first ready pod.
+func (d *database) getPrimaryPod(client kubernetes.Interface) (*core.Pod, error) {
+	pods, err := d.getReadyPods(client)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods {
+		if pods[i].Labels[api.LabelRole] == api.DatabasePodPrimary {
+			return &pods[i], nil
+		}
+	}
+	return &pods[0], nil
+}
+
+// getPod returns the ready pod of the database with the given name, or the
+// ready pod with the given role if name is empty. A replica is any ready pod
+// that is not labelled as primary.
+func (d *database) getPod(client kubernetes.Interface, name, role string) (*core.Pod, error) {
+	if name == "" && role == api.DatabasePodPrimary {
+		return d.getPrimaryPod(client)
+	}
+	pods, err := d.getReadyPods(client)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods {
+		if name != "" {
+			if pods[i].Name == name {
+				return &pods[i], nil
+			}
+		} else if pods[i].Labels[api.LabelRole] != api.DatabasePodPrimary {
+			return &pods[i], nil
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("pod %s is not a ready pod of %s %s/%s", name, d.Kind, d.Namespace, d.Name)
+	}
+	return nil, fmt.Errorf("no ready %s pod found for %s %s/%s", role, d.Kind, d.Namespace, d.Name)
+}
+
+// getReadyPods returns the running and ready pods of the database sorted by
+// name.
+func (d *database) getReadyPods(client kubernetes.Interface) ([]core.Pod, error) {
+	list, err := client.CoreV1().Pods(d.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(d.selector).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var pods []core.Pod
+	for _, pod := range list.Items {
+		if pod.Status.Phase == core.PodRunning && core_util.IsPodReady(&pod) {
+			pods = append(pods, pod)
+		}
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no ready pod found for %s %s/%s", d.Kind, d.Namespace, d.Name)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// clientOptions configure the database client started in a pod.
+type clientOptions struct {
+	// database is the logical database the client connects to. The client
+	// uses its default database if it is empty.
+	database string
+	// query is run by the client, which exits afterwards. If it is empty, the
+	// client reads the queries from stdin.
+	query string
+	// batch is set when the client does not run in a terminal. The client
+	// then stops at the first failed statement and exits with an error code.
+	batch bool
+	// input is set when a script is written to the stdin of the client.
+	input bool
+}
+
+// clientCommand returns the command that starts the database client inside
+// the database container, and whether the client can run an interactive
+// session. The password is read from the environment of the container, it is
+// never part of the command.
+func (d *database) clientCommand(c *credentials, o clientOptions) ([]string, bool, error) {
+	switch db := d.object.(type) {
+	case *api.Postgres:
+		var env []string
+		if db.Spec.TLS != nil {
+			if db.Spec.SSLMode != "" {
+				env = append(env, "PGSSLMODE="+string(db.Spec.SSLMode))
+			}
+			if db.Spec.SSLMode == api.PostgresSSLModeVerifyCA || db.Spec.SSLMode == api.PostgresSSLModeVerifyFull {
+				env = append(env, "PGSSLROOTCERT="+path.Join(postgresCertDir, api.TLSCACertFileName))
+			}
+			if db.Spec.ClientAuthMode == api.ClientAuthModeCert {
+				env = append(env,
+					"PGSSLCERT="+path.Join(postgresCertDir, clientCertFile),
+					"PGSSLKEY="+path.Join(postgresCertDir, clientKeyFile),
+				)
+			}
+		}
+		cmd := []string{"psql", "--host=localhost", "--username=" + c.Username}
+		if o.database != "" {
+			cmd = append(cmd, "--dbname="+o.database)
+		}
+		if o.batch {
+			cmd = append(cmd, "--set=ON_ERROR_STOP=1")
+		}
+		if o.query != "" {
+			cmd = append(cmd, "--command="+o.query)
+		}
+		return withPasswordEnv("PGPASSWORD", postgresPasswordEnv, withEnv(env, cmd...)...), true, nil
+
+	case *api.MySQL:
+		return mysqlCommand(c, o, db.Spec.TLS != nil), true, nil
+	case *api.MariaDB:
+		return mysqlCommand(c, o, db.Spec.TLS != nil), true, nil
+	case *api.PerconaXtraDB:
+		return mysqlCommand(c, o, db.Spec.TLS != nil), true, nil
+
+	case *api.MongoDB:
+		database := o.database
+		if database == "" {
+			database = "admin"
+		}
+		// the mongo shell does not read the password from the environment, so
+		// the shell in the container expands it from the environment of the
+		// container. The mongo shell hides it from the process list.
+		cmd := []string{"sh", "-c", fmt.Sprintf(`exec mongo --password="$%s" "$@"`, mongoPasswordEnv), "mongo", database, "--username=" + c.Username, "--authenticationDatabase=admin"}
+		if db.Spec.TLS != nil && db.Spec.SSLMode != api.SSLModeDisabled {
+			cmd = append(cmd,
+				"--tls",
+				"--tlsCAFile="+path.Join(api.MongoCertDirectory, api.TLSCACertFileName),
+				"--tlsCertificateKeyFile="+path.Join(api.MongoCertDirectory, api.MongoClientFileName),
+			)
+		}
+		if o.batch {
+			cmd = append(cmd, "--quiet")
+		}
+		if o.query != "" {
+			cmd = append(cmd, "--eval", o.query)
+		}
+		return cmd, true, nil
+
+	case *api.Redis:
+		cmd := []string{"redis-cli"}
+		if c.Username != "" && c.Username != "default" {
+			cmd = append(cmd, "--user", c.Username)
+		}
+		if o.database != "" {
+			if _, err := strconv.Atoi(o.database); err != nil {
+				return nil, false, fmt.Errorf("redis databases are numbered, invalid database %q", o.database)
+			}
+			cmd = append(cmd, "-n", o.database)
+		}
+		if db.Spec.Mode == api.RedisModeCluster {
+			cmd = append(cmd, "-c")
+		}
+		if db.Spec.TLS != nil {
+			cmd = append(cmd,
+				"--tls",
+				"--cacert", path.Join(redisCertDir, api.TLSCACertFileName),
+				"--cert", path.Join(redisCertDir, clientCertFile),
+				"--key", path.Join(redisCertDir, clientKeyFile),
+			)
+		}
+		// redis-cli takes a command as separate arguments, so a query is
+		// written to its stdin by the caller instead
+		return cmd, true, nil
+
+	case *api.Elasticsearch:
+		if o.database != "" {
+			return nil, false, fmt.Errorf("selecting a database is not supported for %s", d.Kind)
+		}
+		cmd, err := elasticsearchCommand(db, c, o.query, o.input)
+		return cmd, false, err
+	}
+	return nil, false, fmt.Errorf("connecting to %s is not supported", d.Kind)
+}
+
+// queryOnStdin reports whether the client of the database reads a query from
+// stdin instead of taking it as an argument.
+func (d *database) queryOnStdin() bool {
+	_, ok := d.object.(*api.Redis)
+	return ok
+}
+
+func mysqlCommand(c *credentials, o clientOptions, tls bool) []string {
+	cmd := []string{"mysql", "--host=127.0.0.1", "--user=" + c.Username}
+	if tls {
+		cmd = append(cmd,
+			"--ssl-ca="+path.Join(mysqlCertDir, api.TLSCACertFileName),
+			"--ssl-cert="+path.Join(mysqlCertDir, clientCertFile),
+			"--ssl-key="+path.Join(mysqlCertDir, clientKeyFile),
+		)
+	}
+	if o.database != "" {
+		cmd = append(cmd, "--database="+o.database)
+	}
+	if o.query != "" {
+		cmd = append(cmd, "--execute="+o.query)
+	}
+	return withPasswordEnv("MYSQL_PWD", api.MySQLRootPassword, cmd...)
+}
+
+// elasticsearchCommand returns the curl command that sends a request to the
+// Elasticsearch node in the pod. The request is given as "[METHOD] PATH", a
+// GET of the root path by default. If body is set, the request body is read
+// from stdin and sent with a POST unless another method is given.
+func elasticsearchCommand(db *api.Elasticsearch, c *credentials, request string, body bool) ([]string, error) {
+	method, urlPath := "GET", "/"
+	if body {
+		method = "POST"
+	}
+	switch fields := strings.Fields(request); len(fields) {
+	case 0:
+	case 1:
+		urlPath = fields[0]
+	case 2:
+		method, urlPath = strings.ToUpper(fields[0]), fields[1]
+	default:
+		return nil, fmt.Errorf("invalid request %q, expected [METHOD] PATH", request)
+	}
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+
+	scheme := "http"
+	cmd := []string{"curl", "--silent", "--show-error", "--fail", "--request", method}
+	if db.Spec.EnableSSL {
+		scheme = "https"
+		cmd = append(cmd, "--cacert", path.Join(db.CertSecretVolumeMountPath(api.ElasticsearchConfigDir, api.ElasticsearchHTTPCert), api.TLSCACertFileName))
+	}
+	if body {
+		cmd = append(cmd, "--header", "Content-Type: application/json", "--data-binary", "@-")
+	}
+	cmd = append(cmd, fmt.Sprintf("%s://localhost:%d%s", scheme, api.ElasticsearchRestPort, urlPath))
+	if db.Spec.DisableSecurity || c.Username == "" {
+		return cmd, nil
+	}
+	// the authorization header is built by the shell in the container from
+	// the credentials in its environment, and read by curl from a here-document
+	// so that they are not in the arguments of any process
+	script := fmt.Sprintf(`exec "$@" --header @/dev/fd/3 3<<EOF
+Authorization: Basic $(printf '%%s:%%s' "$%s" "$%s" | base64 | tr -d '\n')
+EOF`, elasticsearchUserEnv, elasticsearchPasswordEnv)
+	return append([]string{"sh", "-c", script, "sh"}, cmd...), nil
+}
+
+// withPasswordEnv returns a command that runs cmd with the environment variable
+// name set to the value of the environment variable from of the container. The
+// password is copied by the shell in the container, so that it is neither in
+// the exec request nor in the arguments of a process.
+func withPasswordEnv(name, from string, cmd ...string) []string {
+	return append([]string{"sh", "-c", fmt.Sprintf(`export %s="$%s"; exec "$@"`, name, from), "sh"}, cmd...)
+}
+
+// withEnv returns a command that runs cmd with the given environment
+// variables set.
+func withEnv(env []string, cmd ...string) []string {
+	if len(env) == 0 {
+		return cmd
+	}
+	return append(append([]string{"env"}, env...), cmd...)
+}
+
+// execInPod runs a command in a container of a pod with the given streams,
+// like kubectl exec does. A terminal is allocated if tty is set and the input
+// is a terminal.
+func execInPod(config *rest.Config, client kubernetes.Interface, pod *core.Pod, container string, command []string, streams genericclioptions.IOStreams, stdin, tty bool) error {
+	t := term.TTY{
+		In:  streams.In,
+		Out: streams.Out,
+		Raw: tty,
+	}
+	if tty && !t.IsTerminalIn() {
+		fmt.Fprintln(streams.ErrOut, "Unable to use a TTY - input is not a terminal or the right kind of file")
+		tty = false
+		t.Raw = false
+	}
+	var sizeQueue remotecommand.TerminalSizeQueue
+	if tty {
+		sizeQueue = t.MonitorSize(t.GetSize())
+	}
+
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&core.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin,
+			Stdout:    true,
+			// stderr is merged into stdout when a terminal is allocated
+			Stderr: !tty,
+			TTY:    tty,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	options := remotecommand.StreamOptions{
+		Stdout:            streams.Out,
+		Tty:               tty,
+		TerminalSizeQueue: sizeQueue,
+	}
+	if stdin {
+		options.Stdin = streams.In
+	}
+	if !tty {
+		options.Stderr = streams.ErrOut
+	}
+	return t.Safe(func() error {
+		return executor.Stream(options)
+	})
+}
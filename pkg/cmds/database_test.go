@@ -0,0 +1,368 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+// The scripts that read the credentials from the environment of the container.
+const (
+	postgresScript      = `export PGPASSWORD="$POSTGRES_PASSWORD"; exec "$@"`
+	mysqlScript         = `export MYSQL_PWD="$MYSQL_ROOT_PASSWORD"; exec "$@"`
+	elasticsearchScript = `exec "$@" --header @/dev/fd/3 3<<EOF
+Authorization: Basic $(printf '%s:%s' "$ELASTIC_USER" "$ELASTIC_PASSWORD" | base64 | tr -d '\n')
+EOF`
+)
+
+func TestClientCommand(t *testing.T) {
+	creds := &credentials{Username: "root", Password: "s3cr'et"}
+	tls := &kmapi.TLSConfig{}
+
+	cases := []struct {
+		name            string
+		object          runtime.Object
+		opts            clientOptions
+		want            []string
+		wantInteractive bool
+		wantErr         string
+	}{
+		{
+			name:            "postgres",
+			object:          &api.Postgres{},
+			want:            []string{"sh", "-c", postgresScript, "sh", "psql", "--host=localhost", "--username=root"},
+			wantInteractive: true,
+		},
+		{
+			name:            "postgres query",
+			object:          &api.Postgres{},
+			opts:            clientOptions{database: "app", query: "select 1", batch: true},
+			want:            []string{"sh", "-c", postgresScript, "sh", "psql", "--host=localhost", "--username=root", "--dbname=app", "--set=ON_ERROR_STOP=1", "--command=select 1"},
+			wantInteractive: true,
+		},
+		{
+			name:   "postgres tls require",
+			object: &api.Postgres{Spec: api.PostgresSpec{TLS: tls, SSLMode: api.PostgresSSLModeRequire}},
+			want: []string{"sh", "-c", postgresScript, "sh",
+				"env", "PGSSLMODE=require", "psql", "--host=localhost", "--username=root"},
+			wantInteractive: true,
+		},
+		{
+			name:            "postgres tls without ssl mode",
+			object:          &api.Postgres{Spec: api.PostgresSpec{TLS: tls}},
+			want:            []string{"sh", "-c", postgresScript, "sh", "psql", "--host=localhost", "--username=root"},
+			wantInteractive: true,
+		},
+		{
+			name: "postgres tls verify-full with client certificate",
+			object: &api.Postgres{Spec: api.PostgresSpec{
+				TLS:            tls,
+				SSLMode:        api.PostgresSSLModeVerifyFull,
+				ClientAuthMode: api.ClientAuthModeCert,
+			}},
+			want: []string{"sh", "-c", postgresScript, "sh",
+				"env", "PGSSLMODE=verify-full",
+				"PGSSLROOTCERT=/tls/certs/client/ca.crt",
+				"PGSSLCERT=/tls/certs/client/client.crt",
+				"PGSSLKEY=/tls/certs/client/client.key",
+				"psql", "--host=localhost", "--username=root"},
+			wantInteractive: true,
+		},
+		{
+			name:            "mysql",
+			object:          &api.MySQL{},
+			opts:            clientOptions{database: "app", query: "select 1"},
+			want:            []string{"sh", "-c", mysqlScript, "sh", "mysql", "--host=127.0.0.1", "--user=root", "--database=app", "--execute=select 1"},
+			wantInteractive: true,
+		},
+		{
+			name:   "mariadb tls",
+			object: &api.MariaDB{Spec: api.MariaDBSpec{TLS: tls}},
+			want: []string{"sh", "-c", mysqlScript, "sh", "mysql", "--host=127.0.0.1", "--user=root",
+				"--ssl-ca=/etc/mysql/certs/ca.crt",
+				"--ssl-cert=/etc/mysql/certs/client.crt",
+				"--ssl-key=/etc/mysql/certs/client.key"},
+			wantInteractive: true,
+		},
+		{
+			name:            "percona xtradb",
+			object:          &api.PerconaXtraDB{},
+			want:            []string{"sh", "-c", mysqlScript, "sh", "mysql", "--host=127.0.0.1", "--user=root"},
+			wantInteractive: true,
+		},
+		{
+			name:   "mongodb",
+			object: &api.MongoDB{},
+			want: []string{"sh", "-c", `exec mongo --password="$MONGO_INITDB_ROOT_PASSWORD" "$@"`,
+				"mongo", "admin", "--username=root", "--authenticationDatabase=admin"},
+			wantInteractive: true,
+		},
+		{
+			name:   "mongodb tls query",
+			object: &api.MongoDB{Spec: api.MongoDBSpec{TLS: tls, SSLMode: api.SSLModeRequireSSL}},
+			opts:   clientOptions{database: "app", query: "db.stats()", batch: true},
+			want: []string{"sh", "-c", `exec mongo --password="$MONGO_INITDB_ROOT_PASSWORD" "$@"`,
+				"mongo", "app", "--username=root", "--authenticationDatabase=admin",
+				"--tls",
+				"--tlsCAFile=/var/run/mongodb/tls/ca.crt",
+				"--tlsCertificateKeyFile=/var/run/mongodb/tls/client.pem",
+				"--quiet", "--eval", "db.stats()"},
+			wantInteractive: true,
+		},
+		{
+			name:   "mongodb tls disabled",
+			object: &api.MongoDB{Spec: api.MongoDBSpec{TLS: tls, SSLMode: api.SSLModeDisabled}},
+			want: []string{"sh", "-c", `exec mongo --password="$MONGO_INITDB_ROOT_PASSWORD" "$@"`,
+				"mongo", "admin", "--username=root", "--authenticationDatabase=admin"},
+			wantInteractive: true,
+		},
+		{
+			name:            "redis",
+			object:          &api.Redis{},
+			opts:            clientOptions{database: "2"},
+			want:            []string{"redis-cli", "--user", "root", "-n", "2"},
+			wantInteractive: true,
+		},
+		{
+			name:   "redis cluster tls",
+			object: &api.Redis{Spec: api.RedisSpec{Mode: api.RedisModeCluster, TLS: tls}},
+			want: []string{"redis-cli", "--user", "root", "-c",
+				"--tls", "--cacert", "/certs/ca.crt", "--cert", "/certs/client.crt", "--key", "/certs/client.key"},
+			wantInteractive: true,
+		},
+		{
+			name:    "redis named database",
+			object:  &api.Redis{},
+			opts:    clientOptions{database: "app"},
+			wantErr: `redis databases are numbered, invalid database "app"`,
+		},
+		{
+			name:   "elasticsearch",
+			object: &api.Elasticsearch{},
+			opts:   clientOptions{query: "/_cat/indices"},
+			want: []string{"sh", "-c", elasticsearchScript, "sh",
+				"curl", "--silent", "--show-error", "--fail", "--request", "GET", "http://localhost:9200/_cat/indices"},
+		},
+		{
+			name:    "elasticsearch database",
+			object:  &api.Elasticsearch{},
+			opts:    clientOptions{database: "app"},
+			wantErr: "selecting a database is not supported for Elasticsearch",
+		},
+		{
+			name:    "unsupported",
+			object:  &api.Memcached{},
+			wantErr: "connecting to Memcached is not supported",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kind := reflect.TypeOf(c.object).Elem().Name()
+			db := &database{Kind: kind, Namespace: "demo", Name: "db", object: c.object}
+			got, interactive, err := db.clientCommand(creds, c.opts)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Errorf("got error %v, want %s", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got command\n%q\nwant\n%q", got, c.want)
+			}
+			if interactive != c.wantInteractive {
+				t.Errorf("got interactive %v, want %v", interactive, c.wantInteractive)
+			}
+			assertNoPassword(t, got, creds.Password)
+		})
+	}
+}
+
+// TestPasswordScripts runs the scripts that read the password in the container
+// with clients that print what they get.
+func TestPasswordScripts(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not installed")
+	}
+	password := `p'a"s$s\`
+	env := append(os.Environ(),
+		"POSTGRES_PASSWORD="+password,
+		"MYSQL_ROOT_PASSWORD="+password,
+		"ELASTIC_USER=elastic",
+		"ELASTIC_PASSWORD="+password,
+	)
+
+	cases := []struct {
+		name    string
+		command []string
+		want    string
+	}{
+		{
+			name:    "postgres",
+			command: withPasswordEnv("PGPASSWORD", postgresPasswordEnv, sh, "-c", `printf %s "$PGPASSWORD"`),
+			want:    password,
+		},
+		{
+			name:    "mysql",
+			command: withPasswordEnv("MYSQL_PWD", api.MySQLRootPassword, sh, "-c", `printf %s "$MYSQL_PWD"`),
+			want:    password,
+		},
+		{
+			name: "elasticsearch",
+			// the client prints the header file given after --header
+			command: []string{sh, "-c", elasticsearchScript, "sh", sh, "-c", `cat "${2#@}"`, "curl"},
+			want:    "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("elastic:"+password)) + "\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cmd := exec.Command(c.command[0], c.command[1:]...)
+			cmd.Env = env
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != c.want {
+				t.Errorf("got %q, want %q", out, c.want)
+			}
+		})
+	}
+}
+
+// assertNoPassword fails the test if the password is part of the command, which
+// would put it in the exec request.
+func assertNoPassword(t *testing.T, command []string, password string) {
+	t.Helper()
+	if password == "" {
+		return
+	}
+	for _, arg := range command {
+		if strings.Contains(arg, password) {
+			t.Errorf("password passed in the command: %q", arg)
+		}
+	}
+}
+
+func TestElasticsearchCommand(t *testing.T) {
+	creds := &credentials{Username: "elastic", Password: "pass"}
+	curl := func(args ...string) []string {
+		return append([]string{"curl", "--silent", "--show-error", "--fail", "--request"}, args...)
+	}
+	withAuth := func(cmd []string) []string {
+		return append([]string{"sh", "-c", elasticsearchScript, "sh"}, cmd...)
+	}
+
+	cases := []struct {
+		name    string
+		spec    api.ElasticsearchSpec
+		creds   *credentials
+		request string
+		body    bool
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "default request",
+			creds: creds,
+			want:  withAuth(curl("GET", "http://localhost:9200/")),
+		},
+		{
+			name:    "path without slash",
+			creds:   creds,
+			request: "_cluster/health",
+			want:    withAuth(curl("GET", "http://localhost:9200/_cluster/health")),
+		},
+		{
+			name:    "method and path",
+			creds:   creds,
+			request: "delete /my-index",
+			want:    withAuth(curl("DELETE", "http://localhost:9200/my-index")),
+		},
+		{
+			name:    "body",
+			creds:   creds,
+			request: "/my-index/_search",
+			body:    true,
+			want: withAuth(curl("POST",
+				"--header", "Content-Type: application/json", "--data-binary", "@-",
+				"http://localhost:9200/my-index/_search")),
+		},
+		{
+			name:    "body with method",
+			creds:   creds,
+			request: "PUT /my-index",
+			body:    true,
+			want: withAuth(curl("PUT",
+				"--header", "Content-Type: application/json", "--data-binary", "@-",
+				"http://localhost:9200/my-index")),
+		},
+		{
+			name:  "tls",
+			spec:  api.ElasticsearchSpec{EnableSSL: true},
+			creds: creds,
+			want: withAuth(curl("GET",
+				"--cacert", "/usr/share/elasticsearch/config/certs/http/ca.crt",
+				"https://localhost:9200/")),
+		},
+		{
+			name:  "security disabled",
+			spec:  api.ElasticsearchSpec{DisableSecurity: true},
+			creds: creds,
+			want:  curl("GET", "http://localhost:9200/"),
+		},
+		{
+			name:  "no credentials",
+			creds: &credentials{},
+			want:  curl("GET", "http://localhost:9200/"),
+		},
+		{
+			name:    "invalid request",
+			creds:   creds,
+			request: "GET /a /b",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := &api.Elasticsearch{Spec: c.spec}
+			got, err := elasticsearchCommand(db, c.creds, c.request, c.body)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got command\n%q\nwant\n%q", got, c.want)
+			}
+			assertNoPassword(t, got, c.creds.Password)
+		})
+	}
+}
@@ -25,9 +25,12 @@ import (
 	"github.com/spf13/cobra"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/describe"
@@ -52,22 +55,36 @@ var (
 		# Describe all postgreses
 		kubedb describe pg
 
+		# Describe a mongodb ops request
+		kubedb describe mongodbopsrequests mops-upgrade
+
+		# Describe a mysql as json or yaml
+		kubedb describe my/mysql-demo -o json
+		kubedb describe my/mysql-demo -o yaml
+
  		Valid resource types include:
     		* all
     		* etcds
     		* elasticsearches
     		* postgreses
+    		* pgbouncers
     		* mysqls
+    		* mariadbs
+    		* perconaxtradbs
+    		* proxysqls
     		* mongodbs
     		* redises
     		* memcacheds
+    		* <database>opsrequests (e.g. mongodbopsrequests, postgresopsrequests)
+    		* <database>autoscalers (e.g. mongodbautoscalers, elasticsearchautoscalers)
 `)
 )
 
 type DescribeOptions struct {
-	CmdParent string
-	Selector  string
-	Namespace string
+	CmdParent    string
+	Selector     string
+	Namespace    string
+	OutputFormat string
 
 	Describer  func(*meta.RESTMapping) (describe.ResourceDescriber, error)
 	NewBuilder func() *resource.Builder
@@ -78,6 +95,7 @@ type DescribeOptions struct {
 	AllNamespaces    bool
 
 	DescriberSettings *describe.DescriberSettings
+	DescriberOptions  describer.Options
 	FilenameOptions   *resource.FilenameOptions
 
 	genericclioptions.IOStreams
@@ -89,6 +107,7 @@ func NewCmdDescribe(parent string, f cmdutil.Factory, streams genericclioptions.
 		DescriberSettings: &describe.DescriberSettings{
 			ShowEvents: true,
 		},
+		DescriberOptions: describer.DefaultOptions(),
 
 		CmdParent: parent,
 
@@ -112,6 +131,8 @@ func NewCmdDescribe(parent string, f cmdutil.Factory, streams genericclioptions.
 	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
 	cmd.Flags().BoolVar(&o.AllNamespaces, "all-namespaces", o.AllNamespaces, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
 	cmd.Flags().BoolVar(&o.DescriberSettings.ShowEvents, "show-events", o.DescriberSettings.ShowEvents, "If true, display events related to the described object.")
+	cmd.Flags().DurationVar(&o.DescriberOptions.CertificateExpiryWindow, "cert-expiry-window", o.DescriberOptions.CertificateExpiryWindow, "TLS certificates that expire within this duration are reported.")
+	cmd.Flags().StringVarP(&o.OutputFormat, "output", "o", o.OutputFormat, "Output format. One of: json|yaml. The description is printed as text if not set.")
 
 	return cmd
 }
@@ -127,14 +148,22 @@ func (o *DescribeOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args [
 		o.EnforceNamespace = false
 	}
 
+	switch o.OutputFormat {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("unsupported output format %q, expected one of json|yaml", o.OutputFormat)
+	}
+
 	if len(args) == 0 && cmdutil.IsFilenameSliceEmpty(o.FilenameOptions.Filenames, o.FilenameOptions.Kustomize) {
 		return fmt.Errorf("You must specify the type of resource to describe. %s\n", cmdutil.SuggestAPIResources(o.CmdParent))
 	}
 
 	o.BuilderArgs = args
 
+	// share the clients and their caches between all the described objects
+	describerFn := describer.DescriberFn(o.DescriberOptions)
 	o.Describer = func(mapping *meta.RESTMapping) (describe.ResourceDescriber, error) {
-		return describer.DescriberFn(f, mapping)
+		return describerFn(f, mapping)
 	}
 
 	o.NewBuilder = f.NewBuilder
@@ -172,9 +201,10 @@ func (o *DescribeOptions) Run() error {
 
 	errs := sets.NewString()
 	first := true
+	var descriptions []*describer.Description
 	for _, info := range infos {
 		mapping := info.ResourceMapping()
-		describer, err := o.Describer(mapping)
+		d, err := o.Describer(mapping)
 		if err != nil {
 			if errs.Has(err.Error()) {
 				continue
@@ -183,7 +213,20 @@ func (o *DescribeOptions) Run() error {
 			errs.Insert(err.Error())
 			continue
 		}
-		s, err := describer.Describe(info.Namespace, info.Name, *o.DescriberSettings)
+		if o.OutputFormat != "" {
+			desc, err := o.describeObject(d, mapping, info.Namespace, info.Name)
+			if err != nil {
+				if errs.Has(err.Error()) {
+					continue
+				}
+				allErrs = append(allErrs, err)
+				errs.Insert(err.Error())
+				continue
+			}
+			descriptions = append(descriptions, desc)
+			continue
+		}
+		s, err := d.Describe(info.Namespace, info.Name, *o.DescriberSettings)
 		if err != nil {
 			if errs.Has(err.Error()) {
 				continue
@@ -200,6 +243,12 @@ func (o *DescribeOptions) Run() error {
 		}
 	}
 
+	if len(descriptions) > 0 {
+		if err := o.printDescriptions(descriptions); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
 	return utilerrors.NewAggregate(allErrs)
 }
 
@@ -215,7 +264,7 @@ func (o *DescribeOptions) DescribeMatchingResources(originalError error, resourc
 	if err != nil {
 		return err
 	}
-	describer, err := o.Describer(mapping)
+	d, err := o.Describer(mapping)
 	if err != nil {
 		return err
 	}
@@ -224,11 +273,20 @@ func (o *DescribeOptions) DescribeMatchingResources(originalError error, resourc
 		return err
 	}
 	isFound := false
+	var descriptions []*describer.Description
 	for ix := range infos {
 		info := infos[ix]
 		if strings.HasPrefix(info.Name, prefix) {
 			isFound = true
-			s, err := describer.Describe(info.Namespace, info.Name, *o.DescriberSettings)
+			if o.OutputFormat != "" {
+				desc, err := o.describeObject(d, mapping, info.Namespace, info.Name)
+				if err != nil {
+					return err
+				}
+				descriptions = append(descriptions, desc)
+				continue
+			}
+			s, err := d.Describe(info.Namespace, info.Name, *o.DescriberSettings)
 			if err != nil {
 				return err
 			}
@@ -238,5 +296,42 @@ func (o *DescribeOptions) DescribeMatchingResources(originalError error, resourc
 	if !isFound {
 		return originalError
 	}
+	if len(descriptions) > 0 {
+		return o.printDescriptions(descriptions)
+	}
 	return nil
 }
+
+// describeObject returns the structured description of an object. Only the
+// KubeDB describers support it, the generic describer used for other kinds
+// only produces text.
+func (o *DescribeOptions) describeObject(d describe.ResourceDescriber, mapping *meta.RESTMapping, namespace, name string) (*describer.Description, error) {
+	od, ok := d.(describer.ObjectDescriber)
+	if !ok {
+		return nil, fmt.Errorf("output format %s is not supported for %s", o.OutputFormat, mapping.GroupVersionKind.Kind)
+	}
+	return od.DescribeObject(namespace, name, *o.DescriberSettings)
+}
+
+// printDescriptions prints a single description as is and several of them
+// wrapped in a DescriptionList.
+func (o *DescribeOptions) printDescriptions(descriptions []*describer.Description) error {
+	var obj interface{} = descriptions[0]
+	if len(descriptions) > 1 {
+		obj = &describer.DescriptionList{
+			APIVersion: describer.DescriptionAPIVersion,
+			Kind:       describer.ResourceKindDescriptionList,
+			Items:      descriptions,
+		}
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	var printer printers.ResourcePrinter = &printers.JSONPrinter{}
+	if o.OutputFormat == "yaml" {
+		printer = &printers.YAMLPrinter{}
+	}
+	return printer.PrintObj(&unstructured.Unstructured{Object: content}, o.Out)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"kubedb.dev/cli/pkg/describer"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+func TestPrintDescriptions(t *testing.T) {
+	newDescription := func(name string) *describer.Description {
+		return &describer.Description{
+			APIVersion: describer.DescriptionAPIVersion,
+			Kind:       describer.ResourceKindDescription,
+			Object: describer.ObjectInfo{
+				APIVersion: "kubedb.com/v1alpha2",
+				Kind:       "Postgres",
+				Name:       name,
+				Namespace:  "demo",
+			},
+			Fields: []describer.Field{{Name: "Status", Value: "Ready"}},
+		}
+	}
+
+	cases := []struct {
+		name      string
+		format    string
+		names     []string
+		wantKind  string
+		wantItems []string
+	}{
+		{name: "single json", format: "json", names: []string{"pg"}, wantKind: describer.ResourceKindDescription},
+		{name: "single yaml", format: "yaml", names: []string{"pg"}, wantKind: describer.ResourceKindDescription},
+		{name: "list json", format: "json", names: []string{"pg", "pg-2"}, wantKind: describer.ResourceKindDescriptionList, wantItems: []string{"pg", "pg-2"}},
+		{name: "list yaml", format: "yaml", names: []string{"pg", "pg-2"}, wantKind: describer.ResourceKindDescriptionList, wantItems: []string{"pg", "pg-2"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := &DescribeOptions{OutputFormat: c.format, IOStreams: streams}
+			var descriptions []*describer.Description
+			for _, name := range c.names {
+				descriptions = append(descriptions, newDescription(name))
+			}
+			if err := o.printDescriptions(descriptions); err != nil {
+				t.Fatal(err)
+			}
+
+			data := out.Bytes()
+			if c.format == "yaml" {
+				if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+					t.Fatalf("expected yaml output, got\n%s", data)
+				}
+				var err error
+				if data, err = yaml.YAMLToJSON(data); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if c.wantItems == nil {
+				var got describer.Description
+				if err := json.Unmarshal(data, &got); err != nil {
+					t.Fatal(err)
+				}
+				if got.Kind != c.wantKind || got.APIVersion != describer.DescriptionAPIVersion {
+					t.Errorf("got %s %s, want %s %s", got.APIVersion, got.Kind, describer.DescriptionAPIVersion, c.wantKind)
+				}
+				if got.Object.Name != c.names[0] || len(got.Fields) != 1 || got.Fields[0].Value != "Ready" {
+					t.Errorf("unexpected description %+v", got)
+				}
+				return
+			}
+
+			var got describer.DescriptionList
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Kind != c.wantKind || got.APIVersion != describer.DescriptionAPIVersion {
+				t.Errorf("got %s %s, want %s %s", got.APIVersion, got.Kind, describer.DescriptionAPIVersion, c.wantKind)
+			}
+			if len(got.Items) != len(c.wantItems) {
+				t.Fatalf("got %d items, want %d", len(got.Items), len(c.wantItems))
+			}
+			for i, item := range got.Items {
+				if item.Kind != describer.ResourceKindDescription || item.Object.Name != c.wantItems[i] {
+					t.Errorf("item %d is %s %s, want %s %s", i, item.Kind, item.Object.Name, describer.ResourceKindDescription, c.wantItems[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+const roleReplica = "replica"
+
+var (
+	execLong = templates.LongDesc(`
+		Run a query or a script with the database client inside a pod of a database.
+		The client runs without a terminal and the command exits with the exit code of the client.
+
+		The query is given with --command and a script with --file. Without either, the script is
+		read from stdin. The client connects with the credentials of the auth secret of the database
+		and the client certificates of the database when TLS is enabled.
+
+		The primary pod is used by default. Use --role=replica to run the query in a ready pod that
+		is not the primary, or --pod to choose the pod.
+
+		For Elasticsearch, the command is a request of the form "[METHOD] PATH" and the file is sent
+		as the JSON body of the request.
+    `)
+
+	execExample = templates.Examples(`
+		# Run a query in the primary pod of a postgres
+		kubectl dba exec pg postgres-demo -c "SELECT version();"
+
+		# Run a migration script against the app database of a mysql
+		kubectl dba exec my mysql-demo --database=app -f migration.sql
+
+		# Run a query in a replica of a mongodb
+		kubectl dba exec mg/mongo-demo -n demo --role=replica -c "rs.status()"
+
+		# Pipe a script to the client in a given pod
+		cat check.sql | kubectl dba exec pg postgres-demo --pod=postgres-demo-1
+
+		# Create an Elasticsearch index with the body read from a file
+		kubectl dba exec es es-demo -c "PUT /products" -f products.json
+`)
+)
+
+type ExecOptions struct {
+	Namespace   string
+	BuilderArgs []string
+
+	Command  string
+	File     string
+	Pod      string
+	Role     string
+	Database string
+
+	config *rest.Config
+	client kubernetes.Interface
+	f      cmdutil.Factory
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdExec(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ExecOptions{
+		Role:      api.DatabasePodPrimary,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "exec (TYPE NAME | TYPE/NAME) [-c QUERY | -f FILE]",
+		Short:   i18n.T("Run a query or a script with the database client in a database pod"),
+		Long:    execLong,
+		Example: execExample,
+		Args:    cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+
+	cmd.Flags().StringVarP(&o.Command, "command", "c", o.Command, "Query to run with the database client.")
+	cmd.Flags().StringVarP(&o.File, "file", "f", o.File, "Script to run with the database client.")
+	cmd.Flags().StringVar(&o.Pod, "pod", o.Pod, "Name of the pod of the database to run the client in.")
+	cmd.Flags().StringVar(&o.Role, "role", o.Role, "Role of the pod to run the client in, one of primary or replica.")
+	cmd.Flags().StringVar(&o.Database, "database", o.Database, "Name of the database the client connects to.")
+
+	return cmd
+}
+
+func (o *ExecOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.BuilderArgs = args
+	o.f = f
+
+	o.config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *ExecOptions) Validate() error {
+	if o.Role != api.DatabasePodPrimary && o.Role != roleReplica {
+		return fmt.Errorf("invalid role %q, must be one of %s or %s", o.Role, api.DatabasePodPrimary, roleReplica)
+	}
+	return nil
+}
+
+func (o *ExecOptions) Run() error {
+	db, err := getDatabase(o.f, o.Namespace, o.BuilderArgs)
+	if err != nil {
+		return err
+	}
+	if o.Command != "" && o.File != "" && db.Kind != api.ResourceKindElasticsearch {
+		return fmt.Errorf("only one of --command or --file can be used for %s", db.Kind)
+	}
+
+	creds, err := db.getCredentials(o.client)
+	if err != nil {
+		return err
+	}
+
+	// the script is streamed to the stdin of the client unless the query
+	// is passed as an argument
+	streams := o.IOStreams
+	opts := clientOptions{
+		database: o.Database,
+		query:    o.Command,
+		batch:    true,
+		input:    o.Command == "" || o.File != "",
+	}
+	switch {
+	case o.File != "":
+		file, err := os.Open(o.File)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		streams.In = file
+	case o.Command != "" && db.queryOnStdin():
+		streams.In = strings.NewReader(o.Command + "\n")
+		opts.query, opts.input = "", true
+	}
+
+	command, _, err := db.clientCommand(creds, opts)
+	if err != nil {
+		return err
+	}
+	pod, err := db.getPod(o.client, o.Pod, o.Role)
+	if err != nil {
+		return err
+	}
+	// the error of a failed client carries its exit code, which is used as
+	// the exit code of the command
+	return execInPod(o.config, o.client, pod, db.container, command, streams, opts.input, false)
+}
@@ -0,0 +1,298 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+	stash "stash.appscode.dev/apimachinery/client/clientset/versioned"
+	stash_v1beta1 "stash.appscode.dev/apimachinery/client/clientset/versioned/typed/stash/v1beta1"
+)
+
+// The fakes in this file serve the commands from in-memory objects. The
+// generated fake clientsets are not vendored, so each fake embeds the
+// interface it implements and only overrides the calls the commands make. Any
+// other call panics on the nil embedded interface, which makes an unexpected
+// API call fail the test loudly.
+
+// fakeCluster holds the objects served by the fake clients.
+type fakeCluster struct {
+	// mu guards the pods, which tests change while the fakes serve them.
+	mu       sync.Mutex
+	pods     []core.Pod
+	services []core.Service
+	secrets  []core.Secret
+	// resources are served through the dynamic client. Listing a resource
+	// that is not in the map fails with NotFound, like a missing CRD does.
+	resources map[schema.GroupVersionResource][]unstructured.Unstructured
+	// conflicts is the number of status patches that fail with a conflict
+	// before the next one succeeds.
+	conflicts int
+	// patches records the patches sent through the dynamic client.
+	patches []fakePatch
+
+	// stash tells whether the Stash CRDs are installed.
+	stash                bool
+	backupConfigurations []stashV1beta1.BackupConfiguration
+	// backupPatches records the BackupConfigurations patched by name.
+	backupPatches map[string]string
+}
+
+type fakePatch struct {
+	name        string
+	subresource string
+	data        string
+}
+
+type fakeKube struct {
+	kubernetes.Interface
+	c *fakeCluster
+}
+
+func (f fakeKube) CoreV1() corev1.CoreV1Interface { return fakeCoreV1{c: f.c} }
+
+func (f fakeKube) Discovery() discovery.DiscoveryInterface { return fakeDiscovery{c: f.c} }
+
+type fakeDiscovery struct {
+	discovery.DiscoveryInterface
+	c *fakeCluster
+}
+
+func (f fakeDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	if !f.c.stash {
+		return nil, nil
+	}
+	return []*metav1.APIResourceList{{
+		GroupVersion: stashV1beta1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: stashV1beta1.ResourcePluralBackupConfiguration, Kind: stashV1beta1.ResourceKindBackupConfiguration}},
+	}}, nil
+}
+
+type fakeCoreV1 struct {
+	corev1.CoreV1Interface
+	c *fakeCluster
+}
+
+func (f fakeCoreV1) Pods(namespace string) corev1.PodInterface {
+	return fakePods{c: f.c, ns: namespace}
+}
+
+func (f fakeCoreV1) Services(namespace string) corev1.ServiceInterface {
+	return fakeServices{c: f.c, ns: namespace}
+}
+
+func (f fakeCoreV1) Secrets(namespace string) corev1.SecretInterface {
+	return fakeSecrets{c: f.c, ns: namespace}
+}
+
+type fakePods struct {
+	corev1.PodInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakePods) List(_ context.Context, opts metav1.ListOptions) (*core.PodList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	f.c.mu.Lock()
+	defer f.c.mu.Unlock()
+	list := &core.PodList{}
+	for _, pod := range f.c.pods {
+		if pod.Namespace == f.ns && selector.Matches(labels.Set(pod.Labels)) {
+			list.Items = append(list.Items, *pod.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+type fakeServices struct {
+	corev1.ServiceInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeServices) Get(_ context.Context, name string, _ metav1.GetOptions) (*core.Service, error) {
+	for _, svc := range f.c.services {
+		if svc.Namespace == f.ns && svc.Name == name {
+			return svc.DeepCopy(), nil
+		}
+	}
+	return nil, kerr.NewNotFound(core.Resource("services"), name)
+}
+
+type fakeSecrets struct {
+	corev1.SecretInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeSecrets) Get(_ context.Context, name string, _ metav1.GetOptions) (*core.Secret, error) {
+	for _, s := range f.c.secrets {
+		if s.Namespace == f.ns && s.Name == name {
+			return s.DeepCopy(), nil
+		}
+	}
+	return nil, kerr.NewNotFound(core.Resource("secrets"), name)
+}
+
+type fakeDynamic struct {
+	dynamic.Interface
+	c *fakeCluster
+}
+
+func (f fakeDynamic) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return fakeResource{c: f.c, gvr: gvr}
+}
+
+type fakeResource struct {
+	dynamic.NamespaceableResourceInterface
+	c   *fakeCluster
+	gvr schema.GroupVersionResource
+	ns  string
+}
+
+func (f fakeResource) Namespace(namespace string) dynamic.ResourceInterface {
+	f.ns = namespace
+	return f
+}
+
+func (f fakeResource) List(_ context.Context, _ metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	items, ok := f.c.resources[f.gvr]
+	if !ok {
+		return nil, kerr.NewNotFound(f.gvr.GroupResource(), "")
+	}
+	list := &unstructured.UnstructuredList{}
+	for _, u := range items {
+		if f.ns == "" || u.GetNamespace() == f.ns {
+			list.Items = append(list.Items, *u.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+func (f fakeResource) Get(_ context.Context, name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	for _, u := range f.c.resources[f.gvr] {
+		if u.GetNamespace() == f.ns && u.GetName() == name {
+			return u.DeepCopy(), nil
+		}
+	}
+	return nil, kerr.NewNotFound(f.gvr.GroupResource(), name)
+}
+
+// Patch records the patch and applies it to the object as a merge patch. A
+// status patch fails with a conflict while the cluster has conflicts left.
+func (f fakeResource) Patch(_ context.Context, name string, _ types.PatchType, data []byte, _ metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	subresource := strings.Join(subresources, "/")
+	f.c.patches = append(f.c.patches, fakePatch{name: name, subresource: subresource, data: string(data)})
+	if subresource == "status" && f.c.conflicts > 0 {
+		f.c.conflicts--
+		return nil, kerr.NewConflict(f.gvr.GroupResource(), name, errors.New("the object has been modified"))
+	}
+	for _, u := range f.c.resources[f.gvr] {
+		if u.GetNamespace() != f.ns || u.GetName() != name {
+			continue
+		}
+		patch := map[string]interface{}{}
+		if err := utiljson.Unmarshal(data, &patch); err != nil {
+			return nil, err
+		}
+		mergePatch(u.Object, patch)
+		return u.DeepCopy(), nil
+	}
+	return nil, kerr.NewNotFound(f.gvr.GroupResource(), name)
+}
+
+// mergePatch applies a JSON merge patch to obj.
+func mergePatch(obj, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(obj, k)
+			continue
+		}
+		p, ok := v.(map[string]interface{})
+		if !ok {
+			obj[k] = v
+			continue
+		}
+		o, ok := obj[k].(map[string]interface{})
+		if !ok {
+			o = map[string]interface{}{}
+			obj[k] = o
+		}
+		mergePatch(o, p)
+	}
+}
+
+type fakeStash struct {
+	stash.Interface
+	c *fakeCluster
+}
+
+func (f fakeStash) StashV1beta1() stash_v1beta1.StashV1beta1Interface {
+	return fakeStashV1beta1{c: f.c}
+}
+
+type fakeStashV1beta1 struct {
+	stash_v1beta1.StashV1beta1Interface
+	c *fakeCluster
+}
+
+func (f fakeStashV1beta1) BackupConfigurations(namespace string) stash_v1beta1.BackupConfigurationInterface {
+	return fakeBackupConfigurations{c: f.c, ns: namespace}
+}
+
+type fakeBackupConfigurations struct {
+	stash_v1beta1.BackupConfigurationInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeBackupConfigurations) List(_ context.Context, _ metav1.ListOptions) (*stashV1beta1.BackupConfigurationList, error) {
+	list := &stashV1beta1.BackupConfigurationList{}
+	for _, bc := range f.c.backupConfigurations {
+		if bc.Namespace == f.ns {
+			list.Items = append(list.Items, *bc.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+func (f fakeBackupConfigurations) Patch(_ context.Context, name string, _ types.PatchType, data []byte, _ metav1.PatchOptions, _ ...string) (*stashV1beta1.BackupConfiguration, error) {
+	if f.c.backupPatches == nil {
+		f.c.backupPatches = map[string]string{}
+	}
+	f.c.backupPatches[name] = string(data)
+	return &stashV1beta1.BackupConfiguration{}, nil
+}
@@ -0,0 +1,334 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	"kmodules.xyz/client-go/discovery"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+	stash "stash.appscode.dev/apimachinery/client/clientset/versioned"
+)
+
+// pausedMessage is the message of the Paused condition set by this plugin.
+const pausedMessage = "Paused by the KubeDB CLI"
+
+var (
+	pauseLong = templates.LongDesc(`
+		Pause the reconciliation of a database by the KubeDB operator.
+		The Paused condition is set in the status of the database, which the operator checks before
+		it reconciles the database. The command waits until the operator has observed the latest
+		generation of the database with the Paused condition set. With --backup, the Stash
+		BackupConfigurations that back up the database are paused as well.
+    `)
+
+	pauseExample = templates.Examples(`
+		# Pause a postgres
+		kubectl dba pause pg postgres-demo
+
+		# Pause a mongodb in the demo namespace and its backups
+		kubectl dba pause mg/mongo-demo -n demo --backup
+`)
+
+	resumeLong = templates.LongDesc(`
+		Resume the reconciliation of a paused database by the KubeDB operator.
+		The Paused condition is removed from the status of the database, so that the operator
+		reconciles the database again. The command waits until the operator has observed the latest
+		generation of the database without the Paused condition. With --backup, the Stash
+		BackupConfigurations that back up the database are resumed as well.
+    `)
+
+	resumeExample = templates.Examples(`
+		# Resume a postgres
+		kubectl dba resume pg postgres-demo
+
+		# Resume a mongodb in the demo namespace and its backups
+		kubectl dba resume mg/mongo-demo -n demo --backup
+`)
+)
+
+// PauseOptions are the options of both the pause and the resume commands.
+type PauseOptions struct {
+	Namespace   string
+	BuilderArgs []string
+	Backup      bool
+	Timeout     time.Duration
+
+	// pause is set for the pause command and unset for the resume command.
+	pause bool
+	// interval is the time between two checks of the database while waiting
+	// for the operator.
+	interval time.Duration
+
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	stashClient   stash.Interface
+	f             cmdutil.Factory
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdPause(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &PauseOptions{
+		Timeout:   time.Minute,
+		pause:     true,
+		interval:  time.Second,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pause (TYPE NAME | TYPE/NAME)",
+		Short:   i18n.T("Pause the reconciliation of a database"),
+		Long:    pauseLong,
+		Example: pauseExample,
+		Args:    cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+	o.addFlags(cmd, "pause")
+
+	return cmd
+}
+
+func NewCmdResume(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &PauseOptions{
+		Timeout:   time.Minute,
+		interval:  time.Second,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "resume (TYPE NAME | TYPE/NAME)",
+		Short:   i18n.T("Resume the reconciliation of a paused database"),
+		Long:    resumeLong,
+		Example: resumeExample,
+		Args:    cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+	o.addFlags(cmd, "resume")
+
+	return cmd
+}
+
+func (o *PauseOptions) addFlags(cmd *cobra.Command, verb string) {
+	cmd.Flags().BoolVar(&o.Backup, "backup", o.Backup, fmt.Sprintf("If true, also %s the Stash BackupConfigurations of the database.", verb))
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait for the operator to observe the new state. Zero means don't wait.")
+}
+
+func (o *PauseOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.BuilderArgs = args
+	o.f = f
+
+	o.kubeClient, err = f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.dynamicClient, err = f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.stashClient, err = stash.NewForConfig(config)
+	return err
+}
+
+func (o *PauseOptions) Run() error {
+	db, err := getDatabase(o.f, o.Namespace, o.BuilderArgs)
+	if err != nil {
+		return err
+	}
+	state := "resumed"
+	if o.pause {
+		state = "paused"
+	}
+
+	if err := o.setPaused(db); err != nil {
+		return err
+	}
+	if o.Timeout > 0 {
+		if err := o.waitObserved(db, state); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(o.Out, "%s.%s/%s %s\n", db.resource.Resource, db.resource.Group, db.Name, state)
+
+	if o.Backup {
+		return o.setBackupsPaused(db, state)
+	}
+	return nil
+}
+
+// getConditions returns the status conditions of the database and the
+// latest version of the database object.
+func (o *PauseOptions) getConditions(db *database) ([]kmapi.Condition, *unstructured.Unstructured, error) {
+	u, err := o.dynamicClient.Resource(db.resource).Namespace(db.Namespace).Get(context.TODO(), db.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, _, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	var conditions []kmapi.Condition
+	if err := json.Unmarshal(data, &conditions); err != nil {
+		return nil, nil, err
+	}
+	return conditions, u, nil
+}
+
+// setPaused sets or removes the Paused condition in the status of the
+// database. The status is patched with the resource version it was read at,
+// so that conditions written by the operator in the meantime are not lost.
+func (o *PauseOptions) setPaused(db *database) error {
+	const attempts = 5
+	for i := 0; ; i++ {
+		conditions, u, err := o.getConditions(db)
+		if err != nil {
+			return err
+		}
+		if o.pause {
+			conditions = kmapi.SetCondition(conditions, kmapi.NewCondition(api.DatabasePaused, pausedMessage, u.GetGeneration()))
+		} else {
+			conditions = kmapi.RemoveCondition(conditions, api.DatabasePaused)
+		}
+
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": u.GetResourceVersion(),
+			},
+			"status": map[string]interface{}{
+				"conditions": conditions,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = o.dynamicClient.Resource(db.resource).Namespace(db.Namespace).Patch(context.TODO(), db.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		if apierrors.IsConflict(err) && i < attempts-1 {
+			continue
+		}
+		return err
+	}
+}
+
+// waitObserved waits until the operator has observed the new pause state of
+// the database. The status patch does not change the generation of the
+// database, so the operator has seen the new state once it has caught up with
+// the latest generation and the status it writes still has the Paused
+// condition set or removed.
+func (o *PauseOptions) waitObserved(db *database, state string) error {
+	err := wait.PollImmediate(o.interval, o.Timeout, func() (bool, error) {
+		conditions, u, err := o.getConditions(db)
+		if err != nil {
+			return false, err
+		}
+		observed, _, err := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+		if err != nil {
+			return false, err
+		}
+		return observed >= u.GetGeneration() && kmapi.IsConditionTrue(conditions, api.DatabasePaused) == o.pause, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for the operator to observe that %s %s/%s is %s", db.Kind, db.Namespace, db.Name, state)
+	}
+	return err
+}
+
+// setBackupsPaused pauses or resumes the BackupConfigurations that have the
+// AppBinding of the database as target.
+func (o *PauseOptions) setBackupsPaused(db *database, state string) error {
+	if !discovery.ExistsGroupKind(o.kubeClient.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupConfiguration) {
+		return fmt.Errorf("unable to find BackupConfigurations, Stash is not installed")
+	}
+
+	list, err := o.stashClient.StashV1beta1().BackupConfigurations(db.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	patch := []byte(fmt.Sprintf(`{"spec":{"paused":%t}}`, o.pause))
+	found := false
+	for _, bc := range list.Items {
+		if !backsUp(&bc, db) {
+			continue
+		}
+		found = true
+		if bc.Spec.Paused == o.pause {
+			fmt.Fprintf(o.Out, "backupconfiguration.%s/%s already %s\n", stashV1beta1.SchemeGroupVersion.Group, bc.Name, state)
+			continue
+		}
+		_, err := o.stashClient.StashV1beta1().BackupConfigurations(db.Namespace).Patch(context.TODO(), bc.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "backupconfiguration.%s/%s %s\n", stashV1beta1.SchemeGroupVersion.Group, bc.Name, state)
+	}
+	if !found {
+		fmt.Fprintf(o.ErrOut, "No BackupConfiguration found for %s %s/%s\n", db.Kind, db.Namespace, db.Name)
+	}
+	return nil
+}
+
+// backsUp reports whether a BackupConfiguration backs up the database, i.e.
+// whether its target is the AppBinding of the database. The AppBinding has
+// the name of the database.
+func backsUp(bc *stashV1beta1.BackupConfiguration, db *database) bool {
+	return bc.Namespace == db.Namespace &&
+		bc.Spec.Target != nil &&
+		bc.Spec.Target.Ref.Kind == appcat.ResourceKindApp &&
+		bc.Spec.Target.Ref.Name == db.Name
+}
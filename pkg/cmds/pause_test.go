@@ -0,0 +1,375 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+)
+
+var postgresResource = api.SchemeGroupVersion.WithResource(api.ResourcePluralPostgres)
+
+func pauseDatabase() *database {
+	return &database{Kind: api.ResourceKindPostgres, Namespace: "demo", Name: "pg", resource: postgresResource}
+}
+
+// pauseCluster returns a cluster with a postgres that has the given status
+// conditions.
+func pauseCluster(t *testing.T, conditions ...kmapi.Condition) *fakeCluster {
+	t.Helper()
+	u := unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetAPIVersion(api.SchemeGroupVersion.String())
+	u.SetKind(api.ResourceKindPostgres)
+	u.SetNamespace("demo")
+	u.SetName("pg")
+	u.SetResourceVersion("7")
+	u.SetGeneration(3)
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedSlice(u.Object, raw, "status", "conditions"); err != nil {
+		t.Fatal(err)
+	}
+	return &fakeCluster{
+		resources: map[schema.GroupVersionResource][]unstructured.Unstructured{postgresResource: {u}},
+	}
+}
+
+func TestSetPaused(t *testing.T) {
+	ready := kmapi.Condition{Type: api.DatabaseReady, Status: core.ConditionTrue, Reason: api.ReadinessCheckSucceeded, ObservedGeneration: 3}
+	paused := kmapi.NewCondition(api.DatabasePaused, pausedMessage, 2)
+
+	cases := []struct {
+		name       string
+		pause      bool
+		conditions []kmapi.Condition
+		conflicts  int
+		// want are the types of the conditions in the patch
+		want        []string
+		wantPatches int
+		wantErr     bool
+	}{
+		{
+			name:        "pause",
+			pause:       true,
+			conditions:  []kmapi.Condition{ready},
+			want:        []string{api.DatabaseReady, api.DatabasePaused},
+			wantPatches: 1,
+		},
+		{
+			name:        "pause paused",
+			pause:       true,
+			conditions:  []kmapi.Condition{ready, paused},
+			want:        []string{api.DatabaseReady, api.DatabasePaused},
+			wantPatches: 1,
+		},
+		{
+			name:        "resume",
+			conditions:  []kmapi.Condition{paused, ready},
+			want:        []string{api.DatabaseReady},
+			wantPatches: 1,
+		},
+		{
+			name:        "retry on conflict",
+			pause:       true,
+			conditions:  []kmapi.Condition{ready},
+			conflicts:   2,
+			want:        []string{api.DatabaseReady, api.DatabasePaused},
+			wantPatches: 3,
+		},
+		{
+			name:        "give up on conflicts",
+			pause:       true,
+			conditions:  []kmapi.Condition{ready},
+			conflicts:   5,
+			wantPatches: 5,
+			wantErr:     true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := pauseCluster(t, c.conditions...)
+			cluster.conflicts = c.conflicts
+			o := &PauseOptions{pause: c.pause, dynamicClient: fakeDynamic{c: cluster}}
+
+			err := o.setPaused(pauseDatabase())
+			if c.wantErr {
+				if !apierrors.IsConflict(err) {
+					t.Errorf("got error %v, want a conflict", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if len(cluster.patches) != c.wantPatches {
+				t.Fatalf("got %d patches, want %d", len(cluster.patches), c.wantPatches)
+			}
+			if c.wantErr {
+				return
+			}
+
+			patch := cluster.patches[len(cluster.patches)-1]
+			if patch.name != "pg" || patch.subresource != "status" {
+				t.Errorf("patched %s of %s, want the status of pg", patch.subresource, patch.name)
+			}
+			var body struct {
+				Metadata struct {
+					ResourceVersion string `json:"resourceVersion"`
+				} `json:"metadata"`
+				Status struct {
+					Conditions []kmapi.Condition `json:"conditions"`
+				} `json:"status"`
+			}
+			if err := json.Unmarshal([]byte(patch.data), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Metadata.ResourceVersion != "7" {
+				t.Errorf("got resource version %q, want the one the status was read at", body.Metadata.ResourceVersion)
+			}
+			var got []string
+			for _, cond := range body.Status.Conditions {
+				got = append(got, cond.Type)
+				if cond.Type != api.DatabasePaused {
+					continue
+				}
+				if cond.Status != core.ConditionTrue || cond.Message != pausedMessage || cond.ObservedGeneration != 3 {
+					t.Errorf("got paused condition %+v", cond)
+				}
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got conditions %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWaitObserved(t *testing.T) {
+	ready := kmapi.Condition{Type: api.DatabaseReady, Status: core.ConditionTrue, Reason: api.ReadinessCheckSucceeded, ObservedGeneration: 3}
+
+	cases := []struct {
+		name  string
+		pause bool
+		// observed is the generation observed by the operator, the database
+		// is at generation 3
+		observed int64
+		// drop removes the Paused condition after it is set, like an
+		// operator that writes a stale status does
+		drop    bool
+		wantErr string
+	}{
+		{
+			name:     "pause",
+			pause:    true,
+			observed: 3,
+		},
+		{
+			name:     "resume",
+			observed: 3,
+		},
+		{
+			name:     "pause before the operator catches up",
+			pause:    true,
+			observed: 2,
+			wantErr:  "timed out waiting for the operator to observe that Postgres demo/pg is paused",
+		},
+		{
+			name:     "resume before the operator catches up",
+			observed: 2,
+			wantErr:  "timed out waiting for the operator to observe that Postgres demo/pg is resumed",
+		},
+		{
+			name:     "paused condition dropped",
+			pause:    true,
+			observed: 3,
+			drop:     true,
+			wantErr:  "timed out waiting for the operator to observe that Postgres demo/pg is paused",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := pauseCluster(t, ready, kmapi.NewCondition(api.DatabasePaused, pausedMessage, 3))
+			u := &cluster.resources[postgresResource][0]
+			if err := unstructured.SetNestedField(u.Object, c.observed, "status", "observedGeneration"); err != nil {
+				t.Fatal(err)
+			}
+			o := &PauseOptions{
+				pause:         c.pause,
+				Timeout:       50 * time.Millisecond,
+				interval:      10 * time.Millisecond,
+				dynamicClient: fakeDynamic{c: cluster},
+			}
+			db := pauseDatabase()
+			if err := o.setPaused(db); err != nil {
+				t.Fatal(err)
+			}
+			if c.drop {
+				o.pause = false
+				if err := o.setPaused(db); err != nil {
+					t.Fatal(err)
+				}
+				o.pause = true
+			}
+			state := "resumed"
+			if c.pause {
+				state = "paused"
+			}
+
+			err := o.waitObserved(db, state)
+			if c.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+			} else if err == nil || err.Error() != c.wantErr {
+				t.Errorf("got error %v, want %s", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func backupConfiguration(namespace, name, kind, target string, paused bool) stashV1beta1.BackupConfiguration {
+	bc := stashV1beta1.BackupConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       stashV1beta1.BackupConfigurationSpec{Paused: paused},
+	}
+	if kind != "" {
+		bc.Spec.Target = &stashV1beta1.BackupTarget{
+			Ref: stashV1beta1.TargetRef{APIVersion: appcat.SchemeGroupVersion.String(), Kind: kind, Name: target},
+		}
+	}
+	return bc
+}
+
+func TestBacksUp(t *testing.T) {
+	cases := []struct {
+		name string
+		bc   stashV1beta1.BackupConfiguration
+		want bool
+	}{
+		{name: "appbinding of the database", bc: backupConfiguration("demo", "b", appcat.ResourceKindApp, "pg", false), want: true},
+		{name: "appbinding of another database", bc: backupConfiguration("demo", "b", appcat.ResourceKindApp, "other", false)},
+		{name: "another kind", bc: backupConfiguration("demo", "b", "StatefulSet", "pg", false)},
+		{name: "another namespace", bc: backupConfiguration("prod", "b", appcat.ResourceKindApp, "pg", false)},
+		{name: "no target", bc: backupConfiguration("demo", "b", "", "", false)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := backsUp(&c.bc, pauseDatabase()); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSetBackupsPaused(t *testing.T) {
+	cases := []struct {
+		name       string
+		pause      bool
+		stash      bool
+		want       map[string]string
+		wantOut    string
+		wantErrOut string
+		wantErr    bool
+		noBackups  bool
+	}{
+		{
+			name:  "pause",
+			pause: true,
+			stash: true,
+			want:  map[string]string{"pg-full": `{"spec":{"paused":true}}`},
+			wantOut: "backupconfiguration.stash.appscode.com/pg-full paused\n" +
+				"backupconfiguration.stash.appscode.com/pg-logs already paused\n",
+		},
+		{
+			name:  "resume",
+			stash: true,
+			want:  map[string]string{"pg-logs": `{"spec":{"paused":false}}`},
+			wantOut: "backupconfiguration.stash.appscode.com/pg-full already resumed\n" +
+				"backupconfiguration.stash.appscode.com/pg-logs resumed\n",
+		},
+		{
+			name:       "no backup configuration",
+			pause:      true,
+			stash:      true,
+			noBackups:  true,
+			wantErrOut: "No BackupConfiguration found for Postgres demo/pg\n",
+		},
+		{
+			name:    "stash not installed",
+			pause:   true,
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := &fakeCluster{stash: c.stash}
+			if !c.noBackups {
+				cluster.backupConfigurations = []stashV1beta1.BackupConfiguration{
+					backupConfiguration("demo", "pg-full", appcat.ResourceKindApp, "pg", false),
+					backupConfiguration("demo", "pg-logs", appcat.ResourceKindApp, "pg", true),
+					backupConfiguration("demo", "other", appcat.ResourceKindApp, "other", false),
+					backupConfiguration("prod", "pg-prod", appcat.ResourceKindApp, "pg", false),
+				}
+			}
+			streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+			o := &PauseOptions{
+				pause:       c.pause,
+				kubeClient:  fakeKube{c: cluster},
+				stashClient: fakeStash{c: cluster},
+				IOStreams:   streams,
+			}
+			state := "resumed"
+			if c.pause {
+				state = "paused"
+			}
+
+			err := o.setBackupsPaused(pauseDatabase(), state)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if len(cluster.backupPatches) != len(c.want) || (len(c.want) > 0 && !reflect.DeepEqual(cluster.backupPatches, c.want)) {
+				t.Errorf("got patches %v, want %v", cluster.backupPatches, c.want)
+			}
+			if out.String() != c.wantOut {
+				t.Errorf("got output\n%s\nwant\n%s", out, c.wantOut)
+			}
+			if errOut.String() != c.wantErrOut {
+				t.Errorf("got errors %q, want %q", errOut, c.wantErrOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,483 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport/spdy"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	core_util "kmodules.xyz/client-go/core/v1"
+)
+
+const portForwardProtocol = "portforward.k8s.io"
+
+var (
+	portForwardLong = templates.LongDesc(`
+		Forward a local port to a database.
+		The connections are forwarded to the pod selected by the primary service of the database, or to
+		the primary pod if the database has no such service. The remote port is the database port of the
+		service, which is also used as the local port unless a local port is given.
+
+		The target pod is looked up again for every new connection, so connections go to the new primary
+		after a failover. The connection URI of the forwarded port is printed, with the password when
+		--show-credentials is given.
+    `)
+
+	portForwardExample = templates.Examples(`
+		# Forward the postgres port of a postgres to the same local port
+		kubectl dba port-forward pg postgres-demo
+
+		# Forward local port 15432 to a postgres and print the connection URI with the password
+		kubectl dba port-forward pg/postgres-demo 15432 --show-credentials
+
+		# Forward a random local port to a mongodb in the demo namespace
+		kubectl dba port-forward mg mongo-demo 0 -n demo
+`)
+)
+
+// etcdClientPort is the port that etcd serves clients on. The apimachinery
+// does not define it.
+const etcdClientPort = 2379
+
+// defaultPorts are the ports that the database engines listen on. They are
+// only used for databases that do not have a primary service.
+var defaultPorts = map[string]int32{
+	api.ResourceKindElasticsearch: api.ElasticsearchRestPort,
+	api.ResourceKindEtcd:          etcdClientPort,
+	api.ResourceKindMariaDB:       api.MySQLDatabasePort,
+	api.ResourceKindMemcached:     api.MemcachedDatabasePort,
+	api.ResourceKindMongoDB:       api.MongoDBDatabasePort,
+	api.ResourceKindMySQL:         api.MySQLDatabasePort,
+	api.ResourceKindPerconaXtraDB: api.MySQLDatabasePort,
+	api.ResourceKindPgBouncer:     api.PgBouncerDatabasePort,
+	api.ResourceKindPostgres:      api.PostgresDatabasePort,
+	api.ResourceKindProxySQL:      api.ProxySQLDatabasePort,
+	api.ResourceKindRedis:         api.RedisDatabasePort,
+}
+
+// primaryServicePortNames are the names of the database port in the primary
+// services created by the operator. The first port of the service is used if
+// none has the name.
+var primaryServicePortNames = map[string]string{
+	api.ResourceKindElasticsearch: api.ElasticsearchRestPortName,
+	api.ResourceKindMariaDB:       api.MySQLPrimaryServicePortName,
+	api.ResourceKindMemcached:     api.MemcachedPrimaryServicePortName,
+	api.ResourceKindMongoDB:       api.MongoDBPrimaryServicePortName,
+	api.ResourceKindMySQL:         api.MySQLPrimaryServicePortName,
+	api.ResourceKindPerconaXtraDB: api.MySQLPrimaryServicePortName,
+	api.ResourceKindPgBouncer:     api.PgBouncerPrimaryServicePortName,
+	api.ResourceKindPostgres:      api.PostgresPrimaryServicePortName,
+	api.ResourceKindProxySQL:      api.ProxySQLPrimaryServicePortName,
+	api.ResourceKindRedis:         api.RedisPrimaryServicePortName,
+}
+
+type PortForwardOptions struct {
+	Namespace       string
+	BuilderArgs     []string
+	LocalPort       int
+	ShowCredentials bool
+
+	config *rest.Config
+	client kubernetes.Interface
+	f      cmdutil.Factory
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdPortForward(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &PortForwardOptions{
+		LocalPort: -1,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "port-forward (TYPE NAME | TYPE/NAME) [LOCAL_PORT]",
+		Short:   i18n.T("Forward a local port to a database"),
+		Long:    portForwardLong,
+		Example: portForwardExample,
+		Args:    cobra.RangeArgs(1, 3),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+
+	cmd.Flags().BoolVar(&o.ShowCredentials, "show-credentials", o.ShowCredentials, "If true, print the password of the database in the connection URI.")
+
+	return cmd
+}
+
+func (o *PortForwardOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	// the local port follows the database, given as TYPE NAME or TYPE/NAME
+	o.BuilderArgs = args
+	if len(args) > 1 {
+		if port, err := strconv.ParseUint(args[len(args)-1], 10, 16); err == nil {
+			o.LocalPort = int(port)
+			o.BuilderArgs = args[:len(args)-1]
+		} else if len(args) == 3 {
+			return fmt.Errorf("invalid local port %q", args[2])
+		}
+	}
+	o.f = f
+
+	o.config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *PortForwardOptions) Run() error {
+	db, err := getDatabase(o.f, o.Namespace, o.BuilderArgs)
+	if err != nil {
+		return err
+	}
+	creds, err := db.getCredentials(o.client)
+	if err != nil {
+		return err
+	}
+
+	pf := &portForwarder{
+		config: o.config,
+		client: o.client,
+		db:     db,
+		errOut: o.ErrOut,
+	}
+	pf.dial = pf.dialPod
+	if err := pf.findService(); err != nil {
+		return err
+	}
+	// check that there is a pod to forward to before listening
+	pod, _, err := pf.target()
+	if err != nil {
+		return err
+	}
+
+	localPort := o.LocalPort
+	if localPort < 0 {
+		localPort = int(pf.port)
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+	if err != nil {
+		return fmt.Errorf("unable to listen on port %d: %v", localPort, err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+
+	target := "pod/" + pod.Name
+	if pf.service != nil {
+		target = "service/" + pf.service.Name
+	}
+	fmt.Fprintf(o.Out, "Forwarding from %s -> %s:%d\n", addr, target, pf.port)
+	shown := &credentials{Username: creds.Username}
+	if o.ShowCredentials {
+		shown = creds
+	}
+	fmt.Fprintf(o.Out, "Connect with %s\n", db.connectionURI(shown, addr.IP.String(), int32(addr.Port)))
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+	stopped := make(chan struct{})
+	go func() {
+		<-stop
+		close(stopped)
+		listener.Close()
+	}()
+	defer pf.close()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-stopped:
+				return nil
+			default:
+				return err
+			}
+		}
+		go pf.handle(conn)
+	}
+}
+
+// portForwarder forwards local connections over a single SPDY connection to
+// the target pod of a database, like kubectl port-forward does. The target is
+// looked up for every local connection, and the SPDY connection is replaced
+// when the target pod changes or the connection is lost.
+type portForwarder struct {
+	config *rest.Config
+	client kubernetes.Interface
+	db     *database
+	errOut io.Writer
+
+	// service is the primary service of the database, nil if the database
+	// does not have one. port is the remote port to forward to, a port of
+	// the service or the default port of the database.
+	service *core.Service
+	port    int32
+
+	// dial opens the SPDY connection to a pod.
+	dial func(pod *core.Pod) (httpstream.Connection, error)
+
+	mu        sync.Mutex
+	pod       string
+	conn      httpstream.Connection
+	requestID int
+}
+
+// findService looks up the primary service of the database and the port of
+// the service to forward to: the database port of the service, or its first
+// port. The default port of the database is used if there is no service.
+func (pf *portForwarder) findService() error {
+	svc, err := pf.client.CoreV1().Services(pf.db.Namespace).Get(context.TODO(), pf.db.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err != nil || len(svc.Spec.Selector) == 0 || len(svc.Spec.Ports) == 0 {
+		pf.port = defaultPorts[pf.db.Kind]
+		return nil
+	}
+
+	pf.service = svc
+	pf.port = svc.Spec.Ports[0].Port
+	for _, port := range svc.Spec.Ports {
+		if port.Name != "" && port.Name == primaryServicePortNames[pf.db.Kind] {
+			pf.port = port.Port
+			break
+		}
+	}
+	return nil
+}
+
+// target returns the pod that connections are forwarded to and the port of
+// the pod.
+func (pf *portForwarder) target() (*core.Pod, int32, error) {
+	if pf.service == nil {
+		pod, err := pf.db.getPrimaryPod(pf.client)
+		return pod, pf.port, err
+	}
+
+	list, err := pf.client.CoreV1().Pods(pf.db.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(pf.service.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	var pod *core.Pod
+	for i := range list.Items {
+		p := &list.Items[i]
+		if p.Status.Phase != core.PodRunning || !core_util.IsPodReady(p) {
+			continue
+		}
+		if pod == nil || p.Labels[api.LabelRole] == api.DatabasePodPrimary {
+			pod = p
+		}
+	}
+	if pod == nil {
+		return nil, 0, fmt.Errorf("no ready pod found for service %s/%s", pf.service.Namespace, pf.service.Name)
+	}
+
+	for _, port := range pf.service.Spec.Ports {
+		if port.Port == pf.port {
+			return pod, containerPort(pod, port), nil
+		}
+	}
+	return pod, pf.port, nil
+}
+
+// containerPort returns the port of the pod that a service port targets.
+func containerPort(pod *core.Pod, port core.ServicePort) int32 {
+	switch {
+	case port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal != 0:
+		return port.TargetPort.IntVal
+	case port.TargetPort.Type == intstr.String && port.TargetPort.StrVal != "":
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == port.TargetPort.StrVal {
+					return p.ContainerPort
+				}
+			}
+		}
+	}
+	return port.Port
+}
+
+// connect returns the SPDY connection to the current target pod, the name of
+// the pod, the port to forward to and the id of the next forwarded connection.
+func (pf *portForwarder) connect() (httpstream.Connection, string, int32, int, error) {
+	pod, port, err := pf.target()
+	if err != nil {
+		return nil, "", 0, 0, err
+	}
+
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	if pf.conn != nil && pf.pod != pod.Name {
+		fmt.Fprintf(pf.errOut, "Target changed from pod %s to pod %s, reconnecting\n", pf.pod, pod.Name)
+		pf.conn.Close()
+		pf.conn = nil
+	}
+	if pf.conn == nil {
+		conn, err := pf.dial(pod)
+		if err != nil {
+			return nil, "", 0, 0, err
+		}
+		pf.conn, pf.pod = conn, pod.Name
+		go func() {
+			<-conn.CloseChan()
+			pf.mu.Lock()
+			defer pf.mu.Unlock()
+			if pf.conn == conn {
+				fmt.Fprintf(pf.errOut, "Lost connection to pod %s\n", pod.Name)
+				pf.conn = nil
+			}
+		}()
+	}
+	pf.requestID++
+	return pf.conn, pod.Name, port, pf.requestID, nil
+}
+
+// dialPod opens a SPDY connection to the port-forward endpoint of a pod.
+func (pf *portForwarder) dialPod(pod *core.Pod) (httpstream.Connection, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
+	if err != nil {
+		return nil, err
+	}
+	req := pf.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+	conn, protocol, err := dialer.Dial(portForwardProtocol)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to pod %s: %v", pod.Name, err)
+	}
+	if protocol != portForwardProtocol {
+		conn.Close()
+		return nil, fmt.Errorf("unable to negotiate the port forward protocol with pod %s", pod.Name)
+	}
+	return conn, nil
+}
+
+// handle copies the data of a local connection to and from a new pair of
+// streams to the target pod.
+func (pf *portForwarder) handle(local net.Conn) {
+	defer local.Close()
+
+	conn, pod, port, requestID, err := pf.connect()
+	if err != nil {
+		fmt.Fprintf(pf.errOut, "Unable to forward connection: %v\n", err)
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set(core.StreamType, core.StreamTypeError)
+	headers.Set(core.PortHeader, strconv.Itoa(int(port)))
+	headers.Set(core.PortForwardRequestIDHeader, strconv.Itoa(requestID))
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		fmt.Fprintf(pf.errOut, "Unable to create the error stream for port %d: %v\n", port, err)
+		return
+	}
+	// the error stream is only read from
+	errorStream.Close()
+	errCh := make(chan error, 1)
+	go func() {
+		msg, err := ioutil.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			errCh <- fmt.Errorf("error reading from the error stream for port %d: %v", port, err)
+		case len(msg) > 0:
+			errCh <- fmt.Errorf("error forwarding port %d to pod %s: %s", port, pod, msg)
+		}
+		close(errCh)
+	}()
+
+	headers.Set(core.StreamType, core.StreamTypeData)
+	dataStream, err := conn.CreateStream(headers)
+	if err != nil {
+		fmt.Fprintf(pf.errOut, "Unable to create the data stream for port %d: %v\n", port, err)
+		return
+	}
+	defer conn.RemoveStreams(errorStream, dataStream)
+
+	localError := make(chan struct{})
+	remoteDone := make(chan struct{})
+	go func() {
+		// the remote side closes the stream when it is done writing
+		io.Copy(local, dataStream)
+		close(remoteDone)
+	}()
+	go func() {
+		// close the stream for writing to let the remote side know we are done
+		defer dataStream.Close()
+		if _, err := io.Copy(dataStream, local); err != nil {
+			close(localError)
+		}
+	}()
+
+	select {
+	case <-remoteDone:
+	case <-localError:
+	}
+	if err := <-errCh; err != nil {
+		fmt.Fprintln(pf.errOut, err)
+	}
+}
+
+func (pf *portForwarder) close() {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	if pf.conn != nil {
+		pf.conn.Close()
+		pf.conn = nil
+	}
+}
@@ -0,0 +1,475 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// fakeStream is a stream of a fakeConnection. Close only closes the stream
+// for writing, like closing a SPDY stream does.
+type fakeStream struct {
+	io.Reader
+	w       io.WriteCloser
+	headers http.Header
+}
+
+func (s *fakeStream) Write(p []byte) (int, error) { return s.w.Write(p) }
+func (s *fakeStream) Close() error                { return s.w.Close() }
+func (s *fakeStream) Reset() error                { return s.w.Close() }
+func (s *fakeStream) Headers() http.Header        { return s.headers }
+func (s *fakeStream) Identifier() uint32          { return 0 }
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// fakeConnection is a port-forward connection to a pod. The remote end of a
+// data stream answers with "<pod>:" followed by what it read once the local
+// end closes the stream, and the error stream returns errMsg.
+type fakeConnection struct {
+	pod    string
+	errMsg string
+
+	mu      sync.Mutex
+	headers []http.Header
+	closed  chan bool
+	once    sync.Once
+}
+
+func newFakeConnection(pod string) *fakeConnection {
+	return &fakeConnection{pod: pod, closed: make(chan bool)}
+}
+
+func (c *fakeConnection) CreateStream(headers http.Header) (httpstream.Stream, error) {
+	headers = headers.Clone()
+	c.mu.Lock()
+	c.headers = append(c.headers, headers)
+	errMsg := c.errMsg
+	c.mu.Unlock()
+
+	if headers.Get(core.StreamType) == core.StreamTypeError {
+		return &fakeStream{Reader: strings.NewReader(errMsg), w: nopWriteCloser{ioutil.Discard}, headers: headers}, nil
+	}
+	localR, remoteW := io.Pipe()
+	remoteR, localW := io.Pipe()
+	go func() {
+		data, _ := ioutil.ReadAll(remoteR)
+		io.WriteString(remoteW, c.pod+":"+string(data))
+		remoteW.Close()
+	}()
+	return &fakeStream{Reader: localR, w: localW, headers: headers}, nil
+}
+
+func (c *fakeConnection) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *fakeConnection) CloseChan() <-chan bool               { return c.closed }
+func (c *fakeConnection) SetIdleTimeout(_ time.Duration)       {}
+func (c *fakeConnection) RemoveStreams(_ ...httpstream.Stream) {}
+
+func (c *fakeConnection) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *fakeConnection) setErrMsg(msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errMsg = msg
+}
+
+func (c *fakeConnection) streamHeaders() []http.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.headers
+}
+
+var forwardSelector = map[string]string{"app.kubernetes.io/instance": "pg"}
+
+func forwardPod(name, role string, ready bool) core.Pod {
+	status := core.ConditionFalse
+	if ready {
+		status = core.ConditionTrue
+	}
+	labels := map[string]string{api.LabelRole: role}
+	for k, v := range forwardSelector {
+		labels[k] = v
+	}
+	return core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: name, Labels: labels},
+		Spec: core.PodSpec{Containers: []core.Container{{
+			Name:  "postgres",
+			Ports: []core.ContainerPort{{Name: "db", ContainerPort: 5433}},
+		}}},
+		Status: core.PodStatus{
+			Phase:      core.PodRunning,
+			Conditions: []core.PodCondition{{Type: core.PodReady, Status: status}},
+		},
+	}
+}
+
+func forwardService(ports ...core.ServicePort) core.Service {
+	return core.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "pg"},
+		Spec:       core.ServiceSpec{Selector: forwardSelector, Ports: ports},
+	}
+}
+
+// setRoles relabels the pods of the cluster with the given roles by name.
+func (c *fakeCluster) setRoles(roles map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.pods {
+		if role, ok := roles[c.pods[i].Name]; ok {
+			c.pods[i].Labels[api.LabelRole] = role
+		}
+	}
+}
+
+func TestPortForwardFindService(t *testing.T) {
+	cases := []struct {
+		name        string
+		kind        string
+		services    []core.Service
+		wantPort    int32
+		wantService bool
+	}{
+		{
+			name:     "no service",
+			kind:     api.ResourceKindPostgres,
+			wantPort: api.PostgresDatabasePort,
+		},
+		{
+			name:     "no service etcd",
+			kind:     api.ResourceKindEtcd,
+			wantPort: etcdClientPort,
+		},
+		{
+			name: "named port",
+			kind: api.ResourceKindPostgres,
+			services: []core.Service{forwardService(
+				core.ServicePort{Name: "metrics", Port: 9187},
+				core.ServicePort{Name: api.PostgresPrimaryServicePortName, Port: 6432},
+			)},
+			wantPort:    6432,
+			wantService: true,
+		},
+		{
+			name: "first port",
+			kind: api.ResourceKindPostgres,
+			services: []core.Service{forwardService(
+				core.ServicePort{Name: "client", Port: 7000},
+				core.ServicePort{Name: "metrics", Port: 9187},
+			)},
+			wantPort:    7000,
+			wantService: true,
+		},
+		{
+			name:        "unnamed port",
+			kind:        api.ResourceKindEtcd,
+			services:    []core.Service{forwardService(core.ServicePort{Port: 2380})},
+			wantPort:    2380,
+			wantService: true,
+		},
+		{
+			name: "service without selector",
+			kind: api.ResourceKindRedis,
+			services: []core.Service{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "pg"},
+				Spec:       core.ServiceSpec{Ports: []core.ServicePort{{Name: "primary", Port: 7000}}},
+			}},
+			wantPort: api.RedisDatabasePort,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := &fakeCluster{services: c.services}
+			pf := &portForwarder{
+				client: fakeKube{c: cluster},
+				db:     &database{Kind: c.kind, Namespace: "demo", Name: "pg"},
+			}
+			if err := pf.findService(); err != nil {
+				t.Fatal(err)
+			}
+			if pf.port != c.wantPort {
+				t.Errorf("got port %d, want %d", pf.port, c.wantPort)
+			}
+			if (pf.service != nil) != c.wantService {
+				t.Errorf("got service %v, want service %v", pf.service != nil, c.wantService)
+			}
+		})
+	}
+}
+
+func TestPortForwardTarget(t *testing.T) {
+	cases := []struct {
+		name     string
+		service  *core.ServicePort
+		pods     []core.Pod
+		wantPod  string
+		wantPort int32
+		wantErr  bool
+	}{
+		{
+			name:     "primary pod without service",
+			pods:     []core.Pod{forwardPod("pg-0", api.DatabasePodStandby, true), forwardPod("pg-1", api.DatabasePodPrimary, true)},
+			wantPod:  "pg-1",
+			wantPort: api.PostgresDatabasePort,
+		},
+		{
+			name:     "primary pod with named target port",
+			service:  &core.ServicePort{Name: "primary", Port: 5432, TargetPort: intstr.FromString("db")},
+			pods:     []core.Pod{forwardPod("pg-0", api.DatabasePodStandby, true), forwardPod("pg-1", api.DatabasePodPrimary, true)},
+			wantPod:  "pg-1",
+			wantPort: 5433,
+		},
+		{
+			name:     "numbered target port",
+			service:  &core.ServicePort{Name: "primary", Port: 5432, TargetPort: intstr.FromInt(6000)},
+			pods:     []core.Pod{forwardPod("pg-0", api.DatabasePodPrimary, true)},
+			wantPod:  "pg-0",
+			wantPort: 6000,
+		},
+		{
+			name:     "primary not ready",
+			service:  &core.ServicePort{Name: "primary", Port: 5432},
+			pods:     []core.Pod{forwardPod("pg-0", api.DatabasePodPrimary, false), forwardPod("pg-1", api.DatabasePodStandby, true)},
+			wantPod:  "pg-1",
+			wantPort: 5432,
+		},
+		{
+			name:    "no ready pod",
+			service: &core.ServicePort{Name: "primary", Port: 5432},
+			pods:    []core.Pod{forwardPod("pg-0", api.DatabasePodPrimary, false)},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := &fakeCluster{pods: c.pods}
+			if c.service != nil {
+				cluster.services = []core.Service{forwardService(*c.service)}
+			}
+			pf := &portForwarder{
+				client: fakeKube{c: cluster},
+				db:     &database{Kind: api.ResourceKindPostgres, Namespace: "demo", Name: "pg", selector: forwardSelector},
+			}
+			if err := pf.findService(); err != nil {
+				t.Fatal(err)
+			}
+			pod, port, err := pf.target()
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if pod.Name != c.wantPod || port != c.wantPort {
+				t.Errorf("got %s:%d, want %s:%d", pod.Name, port, c.wantPod, c.wantPort)
+			}
+		})
+	}
+}
+
+// newTestForwarder returns a forwarder to the pods pg-0 and pg-1 with pg-0 as
+// primary, which records the connections it dials.
+func newTestForwarder(t *testing.T, errOut io.Writer) (*portForwarder, *fakeCluster, func() []*fakeConnection) {
+	t.Helper()
+	cluster := &fakeCluster{
+		pods:     []core.Pod{forwardPod("pg-0", api.DatabasePodPrimary, true), forwardPod("pg-1", api.DatabasePodStandby, true)},
+		services: []core.Service{forwardService(core.ServicePort{Name: "primary", Port: 5432, TargetPort: intstr.FromString("db")})},
+	}
+	pf := &portForwarder{
+		client: fakeKube{c: cluster},
+		db:     &database{Kind: api.ResourceKindPostgres, Namespace: "demo", Name: "pg", selector: forwardSelector},
+		errOut: errOut,
+	}
+	var mu sync.Mutex
+	var dialed []*fakeConnection
+	pf.dial = func(pod *core.Pod) (httpstream.Connection, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		conn := newFakeConnection(pod.Name)
+		dialed = append(dialed, conn)
+		return conn, nil
+	}
+	if err := pf.findService(); err != nil {
+		t.Fatal(err)
+	}
+	return pf, cluster, func() []*fakeConnection {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*fakeConnection(nil), dialed...)
+	}
+}
+
+func TestPortForwardReconnect(t *testing.T) {
+	var errOut bytes.Buffer
+	pf, cluster, dialed := newTestForwarder(t, &errOut)
+	defer pf.close()
+
+	connect := func(wantPod string, wantID int) httpstream.Connection {
+		t.Helper()
+		conn, pod, port, id, err := pf.connect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pod != wantPod || port != 5433 || id != wantID {
+			t.Fatalf("got %s:%d request %d, want %s:5433 request %d", pod, port, id, wantPod, wantID)
+		}
+		return conn
+	}
+
+	// connections to the same pod share the SPDY connection
+	first := connect("pg-0", 1)
+	if connect("pg-0", 2) != first || len(dialed()) != 1 {
+		t.Fatalf("dialed %d connections to the same pod, want 1", len(dialed()))
+	}
+
+	// after a failover the connection to the old primary is replaced
+	cluster.setRoles(map[string]string{"pg-0": api.DatabasePodStandby, "pg-1": api.DatabasePodPrimary})
+	second := connect("pg-1", 3)
+	if second == first || len(dialed()) != 2 {
+		t.Fatalf("dialed %d connections after a failover, want 2", len(dialed()))
+	}
+	if !dialed()[0].isClosed() {
+		t.Error("the connection to the old primary was not closed")
+	}
+	if !strings.Contains(errOut.String(), "Target changed from pod pg-0 to pod pg-1, reconnecting") {
+		t.Errorf("the failover was not reported, got %q", errOut.String())
+	}
+
+	// a lost connection is dialed again for the next local connection
+	second.Close()
+	err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		pf.mu.Lock()
+		defer pf.mu.Unlock()
+		return pf.conn == nil, nil
+	})
+	if err != nil {
+		t.Fatal("the lost connection was not dropped")
+	}
+	if !strings.Contains(errOut.String(), "Lost connection to pod pg-1") {
+		t.Errorf("the lost connection was not reported, got %q", errOut.String())
+	}
+	if third := connect("pg-1", 4); third == second || len(dialed()) != 3 {
+		t.Fatalf("dialed %d connections after losing one, want 3", len(dialed()))
+	}
+}
+
+// forward sends data over a local TCP connection handled by the forwarder and
+// returns the answer.
+func forward(t *testing.T, pf *portForwarder, data string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		pf.handle(conn)
+	}()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if _, err := io.WriteString(client, data); err != nil {
+		t.Fatal(err)
+	}
+	client.(*net.TCPConn).CloseWrite()
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	answer, err := ioutil.ReadAll(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	return string(answer)
+}
+
+func TestPortForwardHandle(t *testing.T) {
+	var errOut bytes.Buffer
+	pf, cluster, dialed := newTestForwarder(t, &errOut)
+	defer pf.close()
+
+	if got := forward(t, pf, "ping"); got != "pg-0:ping" {
+		t.Errorf("got %q, want pg-0:ping", got)
+	}
+	cluster.setRoles(map[string]string{"pg-0": api.DatabasePodStandby, "pg-1": api.DatabasePodPrimary})
+	if got := forward(t, pf, "pong"); got != "pg-1:pong" {
+		t.Errorf("got %q after a failover, want pg-1:pong", got)
+	}
+
+	conns := dialed()
+	if len(conns) != 2 {
+		t.Fatalf("dialed %d connections, want 2", len(conns))
+	}
+	for i, conn := range conns {
+		headers := conn.streamHeaders()
+		if len(headers) != 2 {
+			t.Fatalf("connection %d created %d streams, want an error and a data stream", i, len(headers))
+		}
+		for j, wantType := range []string{core.StreamTypeError, core.StreamTypeData} {
+			h := headers[j]
+			if h.Get(core.StreamType) != wantType || h.Get(core.PortHeader) != "5433" || h.Get(core.PortForwardRequestIDHeader) != strconv.Itoa(i+1) {
+				t.Errorf("stream %d of connection %d has headers %v", j, i, h)
+			}
+		}
+	}
+
+	// errors reported by the pod are printed
+	cluster.setRoles(map[string]string{"pg-1": api.DatabasePodPrimary})
+	conns[1].setErrMsg("connection refused")
+	forward(t, pf, "")
+	if want := "error forwarding port 5433 to pod pg-1: connection refused"; !strings.Contains(errOut.String(), want) {
+		t.Errorf("got errors %q, want %q", errOut.String(), want)
+	}
+}
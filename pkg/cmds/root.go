@@ -57,10 +57,23 @@ func NewKubeDBCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 	ioStreams := genericclioptions.IOStreams{In: in, Out: out, ErrOut: err}
 
 	groups := templates.CommandGroups{
+		{
+			Message: "Basic Commands:",
+			Commands: []*cobra.Command{
+				NewCmdVersions(f, ioStreams),
+			},
+		},
 		{
 			Message: "Troubleshooting and Debugging Commands:",
 			Commands: []*cobra.Command{
 				NewCmdDescribe("kubedb", f, ioStreams),
+				NewCmdConnect(f, ioStreams),
+				NewCmdExec(f, ioStreams),
+				NewCmdShowCredentials(f, ioStreams),
+				NewCmdPortForward(f, ioStreams),
+				NewCmdPause(f, ioStreams),
+				NewCmdResume(f, ioStreams),
+				NewCmdTLS(f, ioStreams),
 				NewCmdCompletion(),
 				v.NewCmdVersion(),
 			},
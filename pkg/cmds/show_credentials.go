@@ -0,0 +1,283 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"k8s.io/kubectl/pkg/util/term"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+	appcat_cs "kmodules.xyz/custom-resources/client/clientset/versioned"
+)
+
+var (
+	showCredentialsLong = templates.LongDesc(`
+		Show the credentials of a database and how to connect to it.
+		The username and password are read from the auth secret of the database, and the host and port
+		from the client configuration of the AppBinding of the database. A connection URI is built for
+		the database engine, with the TLS parameters of the database when TLS is enabled.
+
+		The credentials are only printed to a terminal, unless --yes is given.
+    `)
+
+	showCredentialsExample = templates.Examples(`
+		# Show the credentials of a postgres
+		kubectl dba show-credentials pg postgres-demo
+
+		# Print the connection URI of a mongodb in the demo namespace
+		kubectl dba show-credentials mg/mongo-demo -n demo --format=uri
+
+		# Export the credentials of a mysql to the environment of the current shell
+		eval "$(kubectl dba show-credentials my mysql-demo --yes)"
+`)
+)
+
+// connectionInfo is the information that clients need to connect to a
+// database.
+type connectionInfo struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Host     string `json:"host"`
+	Port     int32  `json:"port"`
+	URI      string `json:"uri"`
+}
+
+type ShowCredentialsOptions struct {
+	Namespace   string
+	BuilderArgs []string
+
+	Format string
+	Yes    bool
+
+	client       kubernetes.Interface
+	appcatClient appcat_cs.Interface
+	f            cmdutil.Factory
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdShowCredentials(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ShowCredentialsOptions{
+		Format:    "env",
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "show-credentials (TYPE NAME | TYPE/NAME)",
+		Short:   i18n.T("Show the credentials and the connection URI of a database"),
+		Long:    showCredentialsLong,
+		Example: showCredentialsExample,
+		Args:    cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+
+	cmd.Flags().StringVar(&o.Format, "format", o.Format, "Output format. One of: env|uri|json.")
+	cmd.Flags().BoolVar(&o.Yes, "yes", o.Yes, "If true, print the credentials even if the output is not a terminal.")
+
+	return cmd
+}
+
+func (o *ShowCredentialsOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.BuilderArgs = args
+	o.f = f
+
+	o.client, err = f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.appcatClient, err = appcat_cs.NewForConfig(config)
+	return err
+}
+
+func (o *ShowCredentialsOptions) Validate() error {
+	switch o.Format {
+	case "env", "uri", "json":
+	default:
+		return fmt.Errorf("unsupported format %q, expected one of env, uri or json", o.Format)
+	}
+	// keep the credentials out of logs and files unless asked for
+	if !o.Yes && !term.IsTerminal(o.Out) {
+		return fmt.Errorf("refusing to print credentials to a non-terminal output, use --yes to print them anyway")
+	}
+	return nil
+}
+
+func (o *ShowCredentialsOptions) Run() error {
+	db, err := getDatabase(o.f, o.Namespace, o.BuilderArgs)
+	if err != nil {
+		return err
+	}
+	creds, err := db.getCredentials(o.client)
+	if err != nil {
+		return err
+	}
+
+	ab, err := o.appcatClient.AppcatalogV1alpha1().AppBindings(db.Namespace).Get(context.TODO(), db.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("AppBinding of %s %s/%s has not been created yet", db.Kind, db.Namespace, db.Name)
+	} else if err != nil {
+		return err
+	}
+	info, err := db.connectionInfo(creds, ab)
+	if err != nil {
+		return err
+	}
+
+	return writeConnectionInfo(o.Out, o.Format, info)
+}
+
+// writeConnectionInfo writes the connection information in the given format:
+// the URI alone, a json object, or shell variable assignments by default.
+func writeConnectionInfo(w io.Writer, format string, info *connectionInfo) error {
+	var err error
+	switch format {
+	case "uri":
+		_, err = fmt.Fprintln(w, info.URI)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(info)
+	default:
+		for _, v := range []struct{ key, value string }{
+			{"USERNAME", info.Username},
+			{"PASSWORD", info.Password},
+			{"HOST", info.Host},
+			{"PORT", strconv.Itoa(int(info.Port))},
+			{"URI", info.URI},
+		} {
+			if _, err = fmt.Fprintf(w, "%s=%s\n", v.key, shellQuote(v.value)); err != nil {
+				break
+			}
+		}
+	}
+	return err
+}
+
+// connectionInfo returns the credentials and the address of the database
+// from its AppBinding, with the connection URI for the database engine.
+func (d *database) connectionInfo(c *credentials, ab *appcat.AppBinding) (*connectionInfo, error) {
+	host, err := ab.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	port, err := ab.Port()
+	if err != nil {
+		return nil, err
+	}
+	return &connectionInfo{
+		Username: c.Username,
+		Password: c.Password,
+		Host:     host,
+		Port:     port,
+		URI:      d.connectionURI(c, host, port),
+	}, nil
+}
+
+// connectionURI returns the URI that clients of the database engine use to
+// connect to the database at the given address. The scheme is chosen by the
+// kind of the database, never taken from the AppBinding, so that the URI is
+// the same whether the address comes from the AppBinding or a port-forward.
+func (d *database) connectionURI(c *credentials, host string, port int32) string {
+	u := url.URL{
+		Host: net.JoinHostPort(host, strconv.Itoa(int(port))),
+	}
+	if c.Username != "" || c.Password != "" {
+		u.User = url.UserPassword(c.Username, c.Password)
+	}
+
+	q := url.Values{}
+	switch db := d.object.(type) {
+	case *api.Postgres:
+		u.Scheme, u.Path = "postgresql", "/postgres"
+		if db.Spec.TLS != nil && db.Spec.SSLMode != "" {
+			q.Set("sslmode", string(db.Spec.SSLMode))
+		}
+	case *api.PgBouncer:
+		u.Scheme, u.Path = "postgresql", "/"
+		if len(db.Spec.Databases) > 0 {
+			u.Path = "/" + db.Spec.Databases[0].Alias
+		}
+		if db.Spec.TLS != nil {
+			q.Set("sslmode", "require")
+		}
+	case *api.MySQL, *api.MariaDB, *api.PerconaXtraDB, *api.ProxySQL:
+		u.Scheme, u.Path = "mysql", "/"
+	case *api.MongoDB:
+		u.Scheme, u.Path = "mongodb", "/"
+		q.Set("authSource", "admin")
+		if db.Spec.TLS != nil && db.Spec.SSLMode != api.SSLModeDisabled {
+			q.Set("tls", "true")
+		}
+	case *api.Redis:
+		u.Scheme = "redis"
+		if db.Spec.TLS != nil {
+			u.Scheme = "rediss"
+		}
+	case *api.Elasticsearch:
+		u.Scheme = "http"
+		if db.Spec.EnableSSL {
+			u.Scheme = "https"
+		}
+	case *api.Etcd:
+		u.Scheme = "http"
+		if db.Spec.TLS != nil {
+			u.Scheme = "https"
+		}
+	case *api.Memcached:
+		u.Scheme = "memcached"
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// shellQuote quotes s so that a POSIX shell reads it as a single word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,399 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	"kubedb.dev/cli/pkg/describer"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+var (
+	tlsInspectLong = templates.LongDesc(`
+		Inspect the TLS certificates of a database.
+		The PEM data in the certificate secrets of the database is parsed to show the subject,
+		SANs, issuer, validity and key type of each certificate. Certificates that expire within
+		the expiry window and chains that do not verify against the CA in the same secret are reported.
+    `)
+
+	tlsInspectExample = templates.Examples(`
+		# Inspect the certificates of a postgres
+		kubectl dba tls inspect pg postgres-demo
+
+		# Warn about the certificates of a mongodb that expire within 60 days
+		kubectl dba tls inspect mg/mongo-demo --expiry-window=1440h
+`)
+
+	tlsReportLong = templates.LongDesc(`
+		Report the expiry of the TLS certificates of all KubeDB databases.
+		The certificates are sorted by expiry, the ones that expire first are listed first.
+		The command exits with a non-zero code if any certificate expires within the given number of days,
+		or if the secret of any certificate is missing or its certificate can not be parsed.
+    `)
+
+	tlsReportExample = templates.Examples(`
+		# Report the certificates of the databases in the current namespace
+		kubectl dba tls report
+
+		# Fail if any certificate of any namespace expires within 14 days
+		kubectl dba tls report --all-namespaces --warn-days=14
+
+		# Report the certificates as json
+		kubectl dba tls report --all-namespaces -o json
+`)
+)
+
+func NewCmdTLS(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "tls",
+		Short:                 i18n.T("Inspect the TLS certificates of databases"),
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(cmd.Help())
+		},
+	}
+	cmd.AddCommand(NewCmdTLSInspect(f, streams))
+	cmd.AddCommand(NewCmdTLSReport(f, streams))
+
+	return cmd
+}
+
+type TLSInspectOptions struct {
+	Namespace    string
+	ExpiryWindow time.Duration
+
+	BuilderArgs []string
+	NewBuilder  func() *resource.Builder
+
+	client kubernetes.Interface
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdTLSInspect(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &TLSInspectOptions{
+		ExpiryWindow: describer.DefaultCertificateExpiryWindow,
+		IOStreams:    streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "inspect (TYPE NAME | TYPE/NAME)",
+		Short:   i18n.T("Inspect the TLS certificates of a database"),
+		Long:    tlsInspectLong,
+		Example: tlsInspectExample,
+		Args:    cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+	cmd.Flags().DurationVar(&o.ExpiryWindow, "expiry-window", o.ExpiryWindow, "Certificates that expire within this duration are reported.")
+
+	return cmd
+}
+
+func (o *TLSInspectOptions) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.BuilderArgs = args
+	o.NewBuilder = f.NewBuilder
+
+	o.client, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *TLSInspectOptions) Run() error {
+	infos, err := o.NewBuilder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		ResourceTypeOrNameArgs(true, o.BuilderArgs...).
+		SingleResourceType().
+		Flatten().
+		Do().
+		Infos()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, info := range infos {
+		u, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unexpected object type %T", info.Object))
+			continue
+		}
+		kind := u.GetKind()
+		tls, err := getTLSConfig(u)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if tls == nil {
+			errs = append(errs, fmt.Errorf("%s %s/%s does not have TLS configured", kind, info.Namespace, info.Name))
+			continue
+		}
+
+		certs := describer.GetCertificates(o.client, info.Namespace, info.Name, tls, o.ExpiryWindow)
+		s, err := describer.RenderCertificates(kind, info.Namespace, info.Name, certs)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(o.Out)
+		}
+		fmt.Fprint(o.Out, s)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// getTLSConfig returns the TLS configuration of a database or nil if the
+// database does not have one.
+func getTLSConfig(u *unstructured.Unstructured) (*kmapi.TLSConfig, error) {
+	content, found, err := unstructured.NestedMap(u.Object, "spec", "tls")
+	if err != nil || !found {
+		return nil, err
+	}
+	var tls kmapi.TLSConfig
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, &tls); err != nil {
+		return nil, err
+	}
+	return &tls, nil
+}
+
+type TLSReportOptions struct {
+	Namespace     string
+	AllNamespaces bool
+	WarnDays      int
+	OutputFormat  string
+
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+
+	genericclioptions.IOStreams
+}
+
+// certificateReportItem is a certificate of a database in the report.
+// NotAfter and DaysLeft are not set if the certificate could not be read.
+type certificateReportItem struct {
+	Namespace  string       `json:"namespace"`
+	Kind       string       `json:"kind"`
+	Name       string       `json:"name"`
+	Alias      string       `json:"alias"`
+	SecretName string       `json:"secretName"`
+	NotAfter   *metav1.Time `json:"notAfter,omitempty"`
+	DaysLeft   *int         `json:"daysLeft,omitempty"`
+	Warnings   []string     `json:"warnings,omitempty"`
+}
+
+func NewCmdTLSReport(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &TLSReportOptions{
+		WarnDays:  int(describer.DefaultCertificateExpiryWindow.Hours() / 24),
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "report",
+		Short:   i18n.T("Report the expiry of the TLS certificates of all databases"),
+		Long:    tlsReportLong,
+		Example: tlsReportExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f))
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+	cmd.Flags().BoolVar(&o.AllNamespaces, "all-namespaces", o.AllNamespaces, "If present, report the databases of all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().IntVar(&o.WarnDays, "warn-days", o.WarnDays, "Exit with a non-zero code if any certificate expires within this number of days. Certificates that can not be read always fail the report.")
+	cmd.Flags().StringVarP(&o.OutputFormat, "output", "o", o.OutputFormat, "Output format. One of: json. The report is printed as a table if not set.")
+
+	return cmd
+}
+
+func (o *TLSReportOptions) Complete(f cmdutil.Factory) error {
+	switch o.OutputFormat {
+	case "", "json":
+	default:
+		return fmt.Errorf("unsupported output format %q, expected json", o.OutputFormat)
+	}
+	if o.WarnDays < 0 {
+		return fmt.Errorf("--warn-days must not be negative")
+	}
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	if o.AllNamespaces {
+		o.Namespace = metav1.NamespaceAll
+	}
+
+	o.kubeClient, err = f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.dynamicClient, err = f.DynamicClient()
+	return err
+}
+
+func (o *TLSReportOptions) Run() error {
+	items, err := o.getReportItems()
+	if err != nil {
+		return err
+	}
+
+	if o.OutputFormat == "json" {
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(items)
+	} else {
+		err = o.printReportTable(items)
+	}
+	if err != nil {
+		return err
+	}
+
+	return checkReportItems(items, o.WarnDays)
+}
+
+// checkReportItems returns an error if any certificate expires within
+// warnDays or could not be read, e.g. because its secret is missing or its PEM
+// data does not parse.
+func checkReportItems(items []certificateReportItem, warnDays int) error {
+	var expiring, unreadable int
+	for _, item := range items {
+		if item.DaysLeft == nil {
+			unreadable++
+		} else if *item.DaysLeft < warnDays {
+			expiring++
+		}
+	}
+
+	var failures []string
+	if expiring > 0 {
+		failures = append(failures, fmt.Sprintf("%d certificate(s) expire within %d days", expiring, warnDays))
+	}
+	if unreadable > 0 {
+		failures = append(failures, fmt.Sprintf("%d certificate(s) could not be read", unreadable))
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, " and "))
+	}
+	return nil
+}
+
+// getReportItems returns the certificates of the databases of every KubeDB
+// kind sorted by expiry. The certificates that could not be read come first.
+// Kinds whose CRD is not installed are skipped.
+func (o *TLSReportOptions) getReportItems() ([]certificateReportItem, error) {
+	window := time.Duration(o.WarnDays) * 24 * time.Hour
+	now := time.Now()
+
+	items := make([]certificateReportItem, 0)
+	for _, e := range catalogEntries {
+		list, err := o.dynamicClient.Resource(api.SchemeGroupVersion.WithResource(e.databasePlural)).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			u := &list.Items[i]
+			tls, err := getTLSConfig(u)
+			if err != nil {
+				return nil, err
+			}
+			if tls == nil {
+				continue
+			}
+			for _, cert := range describer.GetCertificates(o.kubeClient, u.GetNamespace(), u.GetName(), tls, window) {
+				item := certificateReportItem{
+					Namespace:  u.GetNamespace(),
+					Kind:       e.databaseKind,
+					Name:       u.GetName(),
+					Alias:      cert.Alias,
+					SecretName: cert.SecretName,
+					NotAfter:   cert.NotAfter,
+					Warnings:   cert.Warnings,
+				}
+				if cert.NotAfter != nil {
+					daysLeft := int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+					item.DaysLeft = &daysLeft
+				}
+				items = append(items, item)
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].NotAfter == nil || items[j].NotAfter == nil {
+			return items[i].NotAfter == nil && items[j].NotAfter != nil
+		}
+		return items[i].NotAfter.Before(items[j].NotAfter)
+	})
+	return items, nil
+}
+
+func (o *TLSReportOptions) printReportTable(items []certificateReportItem) error {
+	w := printers.GetNewTabWriter(o.Out)
+	fmt.Fprintln(w, "NAMESPACE\tKIND\tNAME\tALIAS\tSECRET\tNOT_AFTER\tDAYS_LEFT")
+	for _, item := range items {
+		notAfter, daysLeft := "<unknown>", "<unknown>"
+		if item.NotAfter != nil {
+			notAfter = item.NotAfter.UTC().Format(time.RFC3339)
+			daysLeft = strconv.Itoa(*item.DaysLeft)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", item.Namespace, item.Kind, item.Name, item.Alias, item.SecretName, notAfter, daysLeft)
+	}
+	return w.Flush()
+}
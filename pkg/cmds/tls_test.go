@@ -0,0 +1,242 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// certificateSecret returns a TLS secret with a self-signed certificate that
+// expires at notAfter.
+func certificateSecret(t *testing.T, namespace, name string, notAfter time.Time) core.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       core.SecretTypeTLS,
+		Data:       map[string][]byte{core.TLSCertKey: crt, "ca.crt": crt},
+	}
+}
+
+// tlsDatabase returns a database with the given certificate aliases. The
+// alias and the secret name of a certificate are separated by a colon, an
+// empty secret name selects the default one.
+func tlsDatabase(kind, namespace, name string, certificates ...string) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetAPIVersion(api.SchemeGroupVersion.String())
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	if len(certificates) > 0 {
+		var specs []interface{}
+		for _, c := range certificates {
+			parts := strings.SplitN(c, ":", 2)
+			spec := map[string]interface{}{"alias": parts[0]}
+			if len(parts) == 2 {
+				spec["secretName"] = parts[1]
+			}
+			specs = append(specs, spec)
+		}
+		u.Object["spec"] = map[string]interface{}{
+			"tls": map[string]interface{}{"certificates": specs},
+		}
+	}
+	return u
+}
+
+func newReportCluster(t *testing.T) *fakeCluster {
+	now := time.Now()
+	day := 24 * time.Hour
+	gvr := func(plural string) schema.GroupVersionResource {
+		return api.SchemeGroupVersion.WithResource(plural)
+	}
+	return &fakeCluster{
+		secrets: []core.Secret{
+			certificateSecret(t, "demo", "pg-server-cert", now.Add(40*day+time.Hour)),
+			certificateSecret(t, "demo", "pg-client-cert", now.Add(10*day+time.Hour)),
+			certificateSecret(t, "prod", "rd-tls", now.Add(100*day+time.Hour)),
+			certificateSecret(t, "prod", "my-server-cert", now.Add(-2*day+time.Hour)),
+		},
+		// Elasticsearch and the other kinds are not installed
+		resources: map[schema.GroupVersionResource][]unstructured.Unstructured{
+			gvr(api.ResourcePluralPostgres): {
+				tlsDatabase(api.ResourceKindPostgres, "demo", "pg", "server", "client"),
+				tlsDatabase(api.ResourceKindPostgres, "demo", "pg-no-tls"),
+			},
+			gvr(api.ResourcePluralMongoDB): {
+				tlsDatabase(api.ResourceKindMongoDB, "demo", "mg", "server:mg-missing"),
+			},
+			gvr(api.ResourcePluralRedis): {
+				tlsDatabase(api.ResourceKindRedis, "prod", "rd", "server:rd-tls"),
+			},
+			gvr(api.ResourcePluralMySQL): {
+				tlsDatabase(api.ResourceKindMySQL, "prod", "my", "server"),
+			},
+		},
+	}
+}
+
+func TestTLSReportItems(t *testing.T) {
+	cases := []struct {
+		name      string
+		namespace string
+		// want lists the items as kind/name/alias=daysLeft, sorted by expiry
+		want []string
+	}{
+		{
+			name:      "namespace",
+			namespace: "demo",
+			want:      []string{"MongoDB/mg/server=?", "Postgres/pg/client=10", "Postgres/pg/server=40"},
+		},
+		{
+			name:      "empty namespace",
+			namespace: "default",
+		},
+		{
+			name:      "all namespaces",
+			namespace: metav1.NamespaceAll,
+			want:      []string{"MongoDB/mg/server=?", "MySQL/my/server=-2", "Postgres/pg/client=10", "Postgres/pg/server=40", "Redis/rd/server=100"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := newReportCluster(t)
+			o := &TLSReportOptions{
+				Namespace:     c.namespace,
+				WarnDays:      30,
+				kubeClient:    fakeKube{c: cluster},
+				dynamicClient: fakeDynamic{c: cluster},
+			}
+			items, err := o.getReportItems()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, item := range items {
+				daysLeft := "?"
+				if item.DaysLeft != nil {
+					daysLeft = strconv.Itoa(*item.DaysLeft)
+				}
+				got = append(got, item.Kind+"/"+item.Name+"/"+item.Alias+"="+daysLeft)
+				if (item.DaysLeft == nil) != (item.NotAfter == nil) {
+					t.Errorf("%s/%s has days left %v and not after %v", item.Name, item.Alias, item.DaysLeft, item.NotAfter)
+				}
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got items %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCheckReportItems(t *testing.T) {
+	days := func(n int) *int { return &n }
+	cases := []struct {
+		name     string
+		daysLeft []*int
+		warnDays int
+		wantErr  string
+	}{
+		{name: "no certificates", warnDays: 30},
+		{name: "all valid", daysLeft: []*int{days(30), days(90)}, warnDays: 30},
+		{name: "expiring", daysLeft: []*int{days(29), days(90)}, warnDays: 30, wantErr: "1 certificate(s) expire within 30 days"},
+		{name: "expired", daysLeft: []*int{days(-3), days(5)}, warnDays: 0, wantErr: "1 certificate(s) expire within 0 days"},
+		{name: "unreadable", daysLeft: []*int{nil, days(90)}, warnDays: 30, wantErr: "1 certificate(s) could not be read"},
+		{name: "unreadable without warn days", daysLeft: []*int{nil}, warnDays: 0, wantErr: "1 certificate(s) could not be read"},
+		{
+			name:     "expiring and unreadable",
+			daysLeft: []*int{nil, nil, days(1), days(2), days(90)},
+			warnDays: 30,
+			wantErr:  "2 certificate(s) expire within 30 days and 2 certificate(s) could not be read",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var items []certificateReportItem
+			for _, d := range c.daysLeft {
+				items = append(items, certificateReportItem{DaysLeft: d})
+			}
+			err := checkReportItems(items, c.warnDays)
+			if c.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+			} else if err == nil || err.Error() != c.wantErr {
+				t.Errorf("got error %v, want %s", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSReportRun(t *testing.T) {
+	cluster := newReportCluster(t)
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	o := &TLSReportOptions{
+		Namespace:     "demo",
+		WarnDays:      30,
+		kubeClient:    fakeKube{c: cluster},
+		dynamicClient: fakeDynamic{c: cluster},
+		IOStreams:     streams,
+	}
+	err := o.Run()
+	if want := "1 certificate(s) expire within 30 days and 1 certificate(s) could not be read"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want a header and 3 certificates:\n%s", len(lines), out)
+	}
+	if fields := strings.Fields(lines[1]); len(fields) != 7 || fields[2] != "mg" || fields[6] != "<unknown>" {
+		t.Errorf("got first row %q, want the unreadable certificate of mg", lines[1])
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	catalog "kubedb.dev/apimachinery/apis/catalog/v1alpha1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	versionsLong = templates.LongDesc(`
+		List the versions available in the KubeDB catalog for a database type.
+		Databases in the cluster that run a deprecated version are reported after the list.
+    `)
+
+	versionsExample = templates.Examples(`
+		# List the supported postgres versions
+		kubectl dba versions postgres
+
+		# List all mongodb versions including the deprecated ones
+		kubectl dba versions mg --show-deprecated
+
+ 		Valid database types include:
+    		* elasticsearch (es)
+    		* etcd (etc)
+    		* mariadb (md)
+    		* memcached (mc)
+    		* mongodb (mg)
+    		* mysql (my)
+    		* perconaxtradb (px)
+    		* pgbouncer (pb)
+    		* postgres (pg)
+    		* proxysql (prx)
+    		* redis (rd)
+`)
+)
+
+type catalogEntry struct {
+	databaseKind   string
+	databasePlural string
+	databaseCode   string
+	versionPlural  string
+}
+
+var catalogEntries = []catalogEntry{
+	{api.ResourceKindElasticsearch, api.ResourcePluralElasticsearch, api.ResourceCodeElasticsearch, catalog.ResourcePluralElasticsearchVersion},
+	{api.ResourceKindEtcd, api.ResourcePluralEtcd, api.ResourceCodeEtcd, catalog.ResourcePluralEtcdVersion},
+	{api.ResourceKindMariaDB, api.ResourcePluralMariaDB, api.ResourceCodeMariaDB, catalog.ResourcePluralMariaDBVersion},
+	{api.ResourceKindMemcached, api.ResourcePluralMemcached, api.ResourceCodeMemcached, catalog.ResourcePluralMemcachedVersion},
+	{api.ResourceKindMongoDB, api.ResourcePluralMongoDB, api.ResourceCodeMongoDB, catalog.ResourcePluralMongoDBVersion},
+	{api.ResourceKindMySQL, api.ResourcePluralMySQL, api.ResourceCodeMySQL, catalog.ResourcePluralMySQLVersion},
+	{api.ResourceKindPerconaXtraDB, api.ResourcePluralPerconaXtraDB, api.ResourceCodePerconaXtraDB, catalog.ResourcePluralPerconaXtraDBVersion},
+	{api.ResourceKindPgBouncer, api.ResourcePluralPgBouncer, api.ResourceCodePgBouncer, catalog.ResourcePluralPgBouncerVersion},
+	{api.ResourceKindPostgres, api.ResourcePluralPostgres, api.ResourceCodePostgres, catalog.ResourcePluralPostgresVersion},
+	{api.ResourceKindProxySQL, api.ResourcePluralProxySQL, api.ResourceCodeProxySQL, catalog.ResourcePluralProxySQLVersion},
+	{api.ResourceKindRedis, api.ResourcePluralRedis, api.ResourceCodeRedis, catalog.ResourcePluralRedisVersion},
+}
+
+// findCatalogEntry matches a database type given as kind, plural or short code.
+func findCatalogEntry(dbType string) (catalogEntry, bool) {
+	dbType = strings.ToLower(dbType)
+	for _, e := range catalogEntries {
+		if dbType == strings.ToLower(e.databaseKind) || dbType == e.databasePlural || dbType == e.databaseCode {
+			return e, true
+		}
+	}
+	return catalogEntry{}, false
+}
+
+// catalogVersion holds the fields that the <Database>Version kinds have in
+// common. The database image lives under db, server or proxysql depending on
+// the kind.
+type catalogVersion struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Version      string       `json:"version"`
+		Distribution string       `json:"distribution,omitempty"`
+		DB           catalogImage `json:"db,omitempty"`
+		Server       catalogImage `json:"server,omitempty"`
+		Proxysql     catalogImage `json:"proxysql,omitempty"`
+		Exporter     catalogImage `json:"exporter,omitempty"`
+		Deprecated   bool         `json:"deprecated,omitempty"`
+	} `json:"spec"`
+}
+
+type catalogImage struct {
+	Image string `json:"image,omitempty"`
+}
+
+func (v catalogVersion) databaseImage() string {
+	for _, img := range []string{v.Spec.DB.Image, v.Spec.Server.Image, v.Spec.Proxysql.Image} {
+		if img != "" {
+			return img
+		}
+	}
+	return ""
+}
+
+type VersionsOptions struct {
+	ShowDeprecated bool
+
+	entry  catalogEntry
+	client dynamic.Interface
+
+	genericclioptions.IOStreams
+}
+
+func NewCmdVersions(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &VersionsOptions{
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "versions DATABASE_TYPE",
+		Short:   i18n.T("List the versions available in the KubeDB catalog"),
+		Long:    versionsLong,
+		Example: versionsExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, args))
+			cmdutil.CheckErr(o.Run())
+		},
+		DisableFlagsInUseLine: true,
+		DisableAutoGenTag:     true,
+	}
+	cmd.Flags().BoolVar(&o.ShowDeprecated, "show-deprecated", o.ShowDeprecated, "If true, deprecated versions are listed as well.")
+
+	return cmd
+}
+
+func (o *VersionsOptions) Complete(f cmdutil.Factory, args []string) error {
+	var ok bool
+	o.entry, ok = findCatalogEntry(args[0])
+	if !ok {
+		return fmt.Errorf("unknown database type %q", args[0])
+	}
+
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.client, err = dynamic.NewForConfig(config)
+	return err
+}
+
+func (o *VersionsOptions) Run() error {
+	list, err := o.client.Resource(catalog.SchemeGroupVersion.WithResource(o.entry.versionPlural)).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	versions := make([]catalogVersion, 0, len(list.Items))
+	deprecated := map[string]bool{}
+	for _, u := range list.Items {
+		var v catalogVersion
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &v); err != nil {
+			return err
+		}
+		deprecated[v.Name] = v.Spec.Deprecated
+		if v.Spec.Deprecated && !o.ShowDeprecated {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Name < versions[j].Name
+	})
+
+	w := printers.GetNewTabWriter(o.Out)
+	fmt.Fprintln(w, "NAME\tVERSION\tDISTRIBUTION\tDB_IMAGE\tEXPORTER_IMAGE\tDEPRECATED")
+	for _, v := range versions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%v\n", v.Name, v.Spec.Version, v.Spec.Distribution, v.databaseImage(), v.Spec.Exporter.Image, v.Spec.Deprecated)
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	return o.printDeprecatedDatabases(deprecated)
+}
+
+// printDeprecatedDatabases reports the databases of all namespaces whose
+// Spec.Version refers to a deprecated catalog entry.
+func (o *VersionsOptions) printDeprecatedDatabases(deprecated map[string]bool) error {
+	list, err := o.client.Resource(api.SchemeGroupVersion.WithResource(o.entry.databasePlural)).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var rows []string
+	for _, u := range list.Items {
+		version, _, err := unstructured.NestedString(u.UnstructuredContent(), "spec", "version")
+		if err != nil {
+			return err
+		}
+		if deprecated[version] {
+			rows = append(rows, fmt.Sprintf("%s\t%s\t%s\n", u.GetNamespace(), u.GetName(), version))
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	sort.Strings(rows)
+
+	fmt.Fprintf(o.Out, "\nWARNING: the following %s databases use a deprecated version:\n", o.entry.databaseKind)
+	w := printers.GetNewTabWriter(o.Out)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tVERSION")
+	for _, row := range rows {
+		fmt.Fprint(w, row)
+	}
+	return w.Flush()
+}
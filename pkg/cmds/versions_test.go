@@ -0,0 +1,185 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmds
+
+import (
+	"testing"
+
+	catalog "kubedb.dev/apimachinery/apis/catalog/v1alpha1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestFindCatalogEntry(t *testing.T) {
+	cases := []struct {
+		dbType string
+		want   string
+	}{
+		{dbType: "Postgres", want: api.ResourceKindPostgres},
+		{dbType: "postgreses", want: api.ResourceKindPostgres},
+		{dbType: "pg", want: api.ResourceKindPostgres},
+		{dbType: "MG", want: api.ResourceKindMongoDB},
+		{dbType: "prx", want: api.ResourceKindProxySQL},
+		{dbType: "oracle"},
+	}
+	for _, c := range cases {
+		t.Run(c.dbType, func(t *testing.T) {
+			e, ok := findCatalogEntry(c.dbType)
+			if ok != (c.want != "") || e.databaseKind != c.want {
+				t.Errorf("got %q, %v, want %q", e.databaseKind, ok, c.want)
+			}
+		})
+	}
+}
+
+// catalogObject returns a catalog version with the database image under the
+// given field of the spec.
+func catalogObject(kind, name, version, imageField, image string, deprecated bool) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version":    version,
+			imageField:   map[string]interface{}{"image": image},
+			"exporter":   map[string]interface{}{"image": "kubedb/exporter:v1"},
+			"deprecated": deprecated,
+		},
+	}}
+	u.SetAPIVersion(catalog.SchemeGroupVersion.String())
+	u.SetKind(kind)
+	u.SetName(name)
+	return u
+}
+
+func versionedDatabase(kind, namespace, name, version string) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"version": version},
+	}}
+	u.SetAPIVersion(api.SchemeGroupVersion.String())
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func TestVersionsRun(t *testing.T) {
+	cluster := &fakeCluster{
+		resources: map[schema.GroupVersionResource][]unstructured.Unstructured{
+			catalog.SchemeGroupVersion.WithResource(catalog.ResourcePluralPostgresVersion): {
+				catalogObject(catalog.ResourceKindPostgresVersion, "13.2", "13.2", "db", "postgres:13.2", false),
+				catalogObject(catalog.ResourceKindPostgresVersion, "10.2", "10.2", "db", "postgres:10.2", true),
+				catalogObject(catalog.ResourceKindPostgresVersion, "11.1", "11.1", "db", "postgres:11.1", false),
+			},
+			catalog.SchemeGroupVersion.WithResource(catalog.ResourcePluralProxySQLVersion): {
+				catalogObject(catalog.ResourceKindProxySQLVersion, "2.0.4", "2.0.4", "proxysql", "proxysql:2.0.4", false),
+			},
+			catalog.SchemeGroupVersion.WithResource(catalog.ResourcePluralRedisVersion): {
+				catalogObject(catalog.ResourceKindRedisVersion, "6.0.6", "6.0.6", "db", "redis:6.0.6", false),
+			},
+			api.SchemeGroupVersion.WithResource(api.ResourcePluralPostgres): {
+				versionedDatabase(api.ResourceKindPostgres, "prod", "pg", "10.2"),
+				versionedDatabase(api.ResourceKindPostgres, "demo", "old", "10.2"),
+				versionedDatabase(api.ResourceKindPostgres, "demo", "new", "13.2"),
+			},
+			api.SchemeGroupVersion.WithResource(api.ResourcePluralProxySQL): {},
+			api.SchemeGroupVersion.WithResource(api.ResourcePluralRedis): {
+				versionedDatabase(api.ResourceKindRedis, "demo", "rd", "6.0.6"),
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		dbType         string
+		showDeprecated bool
+		want           string
+		wantErr        bool
+	}{
+		{
+			name:   "hide deprecated",
+			dbType: "pg",
+			want: `NAME   VERSION   DISTRIBUTION   DB_IMAGE        EXPORTER_IMAGE       DEPRECATED
+11.1   11.1                     postgres:11.1   kubedb/exporter:v1   false
+13.2   13.2                     postgres:13.2   kubedb/exporter:v1   false
+
+WARNING: the following Postgres databases use a deprecated version:
+NAMESPACE   NAME   VERSION
+demo        old    10.2
+prod        pg     10.2
+`,
+		},
+		{
+			name:           "show deprecated",
+			dbType:         "pg",
+			showDeprecated: true,
+			want: `NAME   VERSION   DISTRIBUTION   DB_IMAGE        EXPORTER_IMAGE       DEPRECATED
+10.2   10.2                     postgres:10.2   kubedb/exporter:v1   true
+11.1   11.1                     postgres:11.1   kubedb/exporter:v1   false
+13.2   13.2                     postgres:13.2   kubedb/exporter:v1   false
+
+WARNING: the following Postgres databases use a deprecated version:
+NAMESPACE   NAME   VERSION
+demo        old    10.2
+prod        pg     10.2
+`,
+		},
+		{
+			name:   "proxysql image",
+			dbType: "proxysql",
+			want: `NAME    VERSION   DISTRIBUTION   DB_IMAGE         EXPORTER_IMAGE       DEPRECATED
+2.0.4   2.0.4                    proxysql:2.0.4   kubedb/exporter:v1   false
+`,
+		},
+		{
+			name:   "no deprecated databases",
+			dbType: "rd",
+			want: `NAME    VERSION   DISTRIBUTION   DB_IMAGE      EXPORTER_IMAGE       DEPRECATED
+6.0.6   6.0.6                    redis:6.0.6   kubedb/exporter:v1   false
+`,
+		},
+		{
+			name:    "catalog not installed",
+			dbType:  "mg",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entry, ok := findCatalogEntry(c.dbType)
+			if !ok {
+				t.Fatalf("unknown database type %s", c.dbType)
+			}
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := &VersionsOptions{
+				ShowDeprecated: c.showDeprecated,
+				entry:          entry,
+				client:         fakeDynamic{c: cluster},
+				IOStreams:      streams,
+			}
+
+			err := o.Run()
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if out.String() != c.want {
+				t.Errorf("got output\n%s\nwant\n%s", out, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,526 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	autoscalingapi "kubedb.dev/apimachinery/apis/autoscaling/v1alpha1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
+
+	autoscaling "k8s.io/api/autoscaling/v2beta2"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/describe"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+type autoscalerKind struct {
+	plural       string
+	databaseKind string
+}
+
+// autoscalerKinds lists the Autoscaler kinds from autoscaling.kubedb.com/v1alpha1
+// with their resource name and the kind of database they scale.
+var autoscalerKinds = map[string]autoscalerKind{
+	autoscalingapi.ResourceKindElasticsearchAutoscaler: {autoscalingapi.ResourcePluralElasticsearchAutoscaler, api.ResourceKindElasticsearch},
+	autoscalingapi.ResourceKindEtcdAutoscaler:          {autoscalingapi.ResourcePluralEtcdAutoscaler, api.ResourceKindEtcd},
+	autoscalingapi.ResourceKindMariaDBAutoscaler:       {autoscalingapi.ResourcePluralMariaDBAutoscaler, api.ResourceKindMariaDB},
+	autoscalingapi.ResourceKindMemcachedAutoscaler:     {autoscalingapi.ResourcePluralMemcachedAutoscaler, api.ResourceKindMemcached},
+	autoscalingapi.ResourceKindMongoDBAutoscaler:       {autoscalingapi.ResourcePluralMongoDBAutoscaler, api.ResourceKindMongoDB},
+	autoscalingapi.ResourceKindMySQLAutoscaler:         {autoscalingapi.ResourcePluralMySQLAutoscaler, api.ResourceKindMySQL},
+	autoscalingapi.ResourceKindPerconaXtraDBAutoscaler: {autoscalingapi.ResourcePluralPerconaXtraDBAutoscaler, api.ResourceKindPerconaXtraDB},
+	autoscalingapi.ResourceKindPgBouncerAutoscaler:     {autoscalingapi.ResourcePluralPgBouncerAutoscaler, api.ResourceKindPgBouncer},
+	autoscalingapi.ResourceKindPostgresAutoscaler:      {autoscalingapi.ResourcePluralPostgresAutoscaler, api.ResourceKindPostgres},
+	autoscalingapi.ResourceKindProxySQLAutoscaler:      {autoscalingapi.ResourcePluralProxySQLAutoscaler, api.ResourceKindProxySQL},
+	autoscalingapi.ResourceKindRedisAutoscaler:         {autoscalingapi.ResourcePluralRedisAutoscaler, api.ResourceKindRedis},
+}
+
+// autoscaler holds the fields shared by the Autoscaler kinds. Elasticsearch and
+// MongoDB autoscalers point to their database with DatabaseRef and carry compute
+// and storage policies, the others use ScaleTargetRef with replica bounds and
+// metrics in the style of a HorizontalPodAutoscaler.
+type autoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		DatabaseRef    *core.LocalObjectReference `json:"databaseRef,omitempty"`
+		ScaleTargetRef *core.LocalObjectReference `json:"scaleTargetRef,omitempty"`
+		MinReplicas    *int32                     `json:"minReplicas,omitempty"`
+		MaxReplicas    int32                      `json:"maxReplicas,omitempty"`
+		Metrics        []autoscaling.MetricSpec   `json:"metrics,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		LastScaleTime   *metav1.Time      `json:"lastScaleTime,omitempty"`
+		CurrentReplicas int32             `json:"currentReplicas,omitempty"`
+		DesiredReplicas int32             `json:"desiredReplicas,omitempty"`
+		Conditions      []kmapi.Condition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+func (a *autoscaler) targetName() string {
+	if a.Spec.DatabaseRef != nil {
+		return a.Spec.DatabaseRef.Name
+	}
+	if a.Spec.ScaleTargetRef != nil {
+		return a.Spec.ScaleTargetRef.Name
+	}
+	return ""
+}
+
+// decodeAutoscaler converts an Autoscaler read through the dynamic client into
+// its common fields and into the typed object of its kind.
+func decodeAutoscaler(u *unstructured.Unstructured) (*autoscaler, runtime.Object, error) {
+	var item autoscaler
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &item); err != nil {
+		return nil, nil, err
+	}
+	obj, err := scheme.Scheme.New(autoscalingapi.SchemeGroupVersion.WithKind(u.GetKind()))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), obj); err != nil {
+		return nil, nil, err
+	}
+	return &item, obj, nil
+}
+
+type AutoscalerDescriber struct {
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	kind    string
+}
+
+func (d *AutoscalerDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
+	if err != nil {
+		return "", err
+	}
+	return RenderText(desc)
+}
+
+func (d *AutoscalerDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	info, ok := autoscalerKinds[d.kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown Autoscaler kind %s", d.kind)
+	}
+
+	u, err := d.dynamic.Resource(autoscalingapi.SchemeGroupVersion.WithResource(info.plural)).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	item, obj, err := decodeAutoscaler(u)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	if describerSettings.ShowEvents {
+		events, err = getEvents(d.client, item.Namespace, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d.describeAutoscaler(item, obj, info.databaseKind, events)
+}
+
+func (d *AutoscalerDescriber) describeAutoscaler(item *autoscaler, obj runtime.Object, databaseKind string, events []Event) (*Description, error) {
+	desc := newDescription(autoscalingapi.SchemeGroupVersion.WithKind(d.kind), item.ObjectMeta)
+	desc.addField("Database", "%s/%s", databaseKind, item.targetName())
+	if item.Spec.ScaleTargetRef != nil {
+		desc.addField("Current Replicas", "%d", item.Status.CurrentReplicas)
+		desc.addField("Desired Replicas", "%d", item.Status.DesiredReplicas)
+		desc.addField("Last Scale Time", "%s", timeToString(item.Status.LastScaleTime))
+	}
+
+	desc.addSection(&Section{Title: "Policies", Sections: getAutoscalerPolicies(item, obj)})
+
+	resources, err := d.getDatabaseResources(item.Namespace, item.targetName(), databaseKind)
+	if err != nil {
+		return nil, err
+	}
+	desc.addSection(resources)
+
+	desc.addSection(getConditions(item.Status.Conditions))
+
+	desc.Events = events
+	return desc, nil
+}
+
+type databaseComponent struct {
+	component string
+	replicas  *int32
+	resources core.ResourceRequirements
+	storage   *core.PersistentVolumeClaimSpec
+}
+
+// getDatabaseResources lists the resources currently requested by each
+// component of the database that the autoscaler targets, so that they can be
+// compared against the allowed range of the compute and storage policies.
+func (d *AutoscalerDescriber) getDatabaseResources(namespace, name, databaseKind string) (*Section, error) {
+	section := &Section{Title: "Database Resources"}
+	u, err := d.dynamic.Resource(api.SchemeGroupVersion.WithResource(databasePlurals[databaseKind])).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if kerr.IsNotFound(err) {
+		section.addNote("%s/%s not found.", databaseKind, name)
+		return section, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	components, err := getDatabaseComponents(u)
+	if err != nil {
+		return nil, err
+	}
+
+	section.Table = newTable("Component", "Replicas", "Requests", "Limits", "Storage")
+	for _, c := range components {
+		storage := ValueNone
+		if c.storage != nil {
+			if q, ok := c.storage.Resources.Requests[core.ResourceStorage]; ok {
+				storage = q.String()
+			}
+		}
+		section.Table.addRow(
+			c.component,
+			formatInt32Ptr(c.replicas),
+			formatResourceList(c.resources.Requests),
+			formatResourceList(c.resources.Limits),
+			storage,
+		)
+	}
+	return section, nil
+}
+
+// databasePlurals maps the database kinds to their resource names.
+var databasePlurals = map[string]string{
+	api.ResourceKindElasticsearch: api.ResourcePluralElasticsearch,
+	api.ResourceKindEtcd:          api.ResourcePluralEtcd,
+	api.ResourceKindMariaDB:       api.ResourcePluralMariaDB,
+	api.ResourceKindMemcached:     api.ResourcePluralMemcached,
+	api.ResourceKindMongoDB:       api.ResourcePluralMongoDB,
+	api.ResourceKindMySQL:         api.ResourcePluralMySQL,
+	api.ResourceKindPerconaXtraDB: api.ResourcePluralPerconaXtraDB,
+	api.ResourceKindPgBouncer:     api.ResourcePluralPgBouncer,
+	api.ResourceKindPostgres:      api.ResourcePluralPostgres,
+	api.ResourceKindProxySQL:      api.ResourcePluralProxySQL,
+	api.ResourceKindRedis:         api.ResourcePluralRedis,
+}
+
+// getDatabaseComponents splits a database into the components that can be
+// scaled on their own. Elasticsearch and MongoDB are broken down by topology,
+// every other database is a single component.
+func getDatabaseComponents(u *unstructured.Unstructured) ([]databaseComponent, error) {
+	switch u.GetKind() {
+	case api.ResourceKindElasticsearch:
+		var db api.Elasticsearch
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &db); err != nil {
+			return nil, err
+		}
+		if t := db.Spec.Topology; t != nil {
+			// the same tiers as the node topology of the database description
+			var components []databaseComponent
+			for _, tier := range []struct {
+				component string
+				node      *api.ElasticsearchNode
+			}{
+				{"Master", &t.Master},
+				{"Ingest", &t.Ingest},
+				{"Data", t.Data},
+				{"Data Content", t.DataContent},
+				{"Data Hot", t.DataHot},
+				{"Data Warm", t.DataWarm},
+				{"Data Cold", t.DataCold},
+				{"Data Frozen", t.DataFrozen},
+				{"ML", t.ML},
+				{"Transform", t.Transform},
+				{"Coordinating", t.Coordinating},
+			} {
+				if tier.node != nil {
+					components = append(components, databaseComponent{component: tier.component, replicas: tier.node.Replicas, resources: tier.node.Resources, storage: tier.node.Storage})
+				}
+			}
+			return components, nil
+		}
+		return []databaseComponent{
+			{component: "Node", replicas: db.Spec.Replicas, resources: db.Spec.PodTemplate.Spec.Resources, storage: db.Spec.Storage},
+		}, nil
+	case api.ResourceKindMongoDB:
+		var db api.MongoDB
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &db); err != nil {
+			return nil, err
+		}
+		if t := db.Spec.ShardTopology; t != nil {
+			return []databaseComponent{
+				{component: fmt.Sprintf("Shard (%d shards)", t.Shard.Shards), replicas: &t.Shard.Replicas, resources: t.Shard.PodTemplate.Spec.Resources, storage: t.Shard.Storage},
+				{component: "ConfigServer", replicas: &t.ConfigServer.Replicas, resources: t.ConfigServer.PodTemplate.Spec.Resources, storage: t.ConfigServer.Storage},
+				{component: "Mongos", replicas: &t.Mongos.Replicas, resources: t.Mongos.PodTemplate.Spec.Resources},
+			}, nil
+		}
+		component := databaseComponent{component: "Standalone", replicas: db.Spec.Replicas, storage: db.Spec.Storage}
+		if db.Spec.ReplicaSet != nil {
+			component.component = "ReplicaSet"
+		}
+		if db.Spec.PodTemplate != nil {
+			component.resources = db.Spec.PodTemplate.Spec.Resources
+		}
+		return []databaseComponent{component}, nil
+	}
+
+	// The remaining databases share the replicas, storage and podTemplate fields.
+	var db struct {
+		Spec struct {
+			Replicas    *int32                          `json:"replicas,omitempty"`
+			Storage     *core.PersistentVolumeClaimSpec `json:"storage,omitempty"`
+			PodTemplate *struct {
+				Spec struct {
+					Resources core.ResourceRequirements `json:"resources,omitempty"`
+				} `json:"spec,omitempty"`
+			} `json:"podTemplate,omitempty"`
+		} `json:"spec"`
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &db); err != nil {
+		return nil, err
+	}
+	component := databaseComponent{component: u.GetKind(), replicas: db.Spec.Replicas, storage: db.Spec.Storage}
+	if db.Spec.PodTemplate != nil {
+		component.resources = db.Spec.PodTemplate.Spec.Resources
+	}
+	return []databaseComponent{component}, nil
+}
+
+// getAutoscalers returns the Autoscalers that target the given database along
+// with their policies. It returns nil if the Autoscaler CRDs are not installed.
+func getAutoscalers(dc dynamic.Interface, databaseKind, namespace, name string) ([]AutoscalerRef, error) {
+	var plural string
+	for _, info := range autoscalerKinds {
+		if info.databaseKind == databaseKind {
+			plural = info.plural
+		}
+	}
+	if plural == "" {
+		return nil, nil
+	}
+
+	list, err := dc.Resource(autoscalingapi.SchemeGroupVersion.WithResource(plural)).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if kerr.IsNotFound(err) {
+		// autoscaling.kubedb.com CRDs are not installed
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	autoscalers := make([]AutoscalerRef, 0)
+	for i := range list.Items {
+		item, obj, err := decodeAutoscaler(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		if item.targetName() != name {
+			continue
+		}
+		autoscalers = append(autoscalers, AutoscalerRef{
+			Name:              item.Name,
+			Kind:              item.Kind,
+			CreationTimestamp: item.CreationTimestamp,
+			Policies:          getAutoscalerPolicies(item, obj),
+		})
+	}
+	return autoscalers, nil
+}
+
+func describeAutoscalerRefs(autoscalers []AutoscalerRef, w describe.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	if len(autoscalers) == 0 {
+		w.Write(LEVEL_0, "Autoscalers:\t%s\n", ValueNone)
+		return
+	}
+	w.Write(LEVEL_0, "Autoscalers:\n")
+	for _, a := range autoscalers {
+		w.Write(LEVEL_1, "%s:\n", a.Name)
+		w.Write(LEVEL_2, "Age:\t%s\n", duration.HumanDuration(timeNow().Sub(a.CreationTimestamp.Time)))
+		for i := range a.Policies {
+			describeSection(LEVEL_2, &a.Policies[i], w)
+		}
+	}
+}
+
+type computePolicy struct {
+	component string
+	spec      *autoscalingapi.ComputeAutoscalerSpec
+}
+
+type storagePolicy struct {
+	component string
+	spec      *autoscalingapi.StorageAutoscalerSpec
+}
+
+// getAutoscalerPolicies returns the per component compute and storage policies
+// of an Elasticsearch or MongoDB autoscaler, or the replica bounds and metrics
+// for the other kinds.
+func getAutoscalerPolicies(item *autoscaler, obj runtime.Object) []Section {
+	var (
+		compute          []computePolicy
+		storage          []storagePolicy
+		disableScaleDown bool
+	)
+	switch a := obj.(type) {
+	case *autoscalingapi.ElasticsearchAutoscaler:
+		if c := a.Spec.Compute; c != nil {
+			compute = appendComputePolicy(compute, "Node", c.Node)
+			if t := c.Topology; t != nil {
+				compute = appendComputePolicy(compute, "Master", t.Master)
+				compute = appendComputePolicy(compute, "Ingest", t.Ingest)
+				compute = appendComputePolicy(compute, "Data", t.Data)
+			}
+			disableScaleDown = c.DisableScaleDown
+		}
+		if s := a.Spec.Storage; s != nil {
+			storage = appendStoragePolicy(storage, "Node", s.Node)
+			if t := s.Topology; t != nil {
+				storage = appendStoragePolicy(storage, "Master", t.Master)
+				storage = appendStoragePolicy(storage, "Ingest", t.Ingest)
+				storage = appendStoragePolicy(storage, "Data", t.Data)
+			}
+		}
+	case *autoscalingapi.MongoDBAutoscaler:
+		if c := a.Spec.Compute; c != nil {
+			compute = appendComputePolicy(compute, "Standalone", c.Standalone)
+			compute = appendComputePolicy(compute, "ReplicaSet", c.ReplicaSet)
+			compute = appendComputePolicy(compute, "ConfigServer", c.ConfigServer)
+			compute = appendComputePolicy(compute, "Shard", c.Shard)
+			compute = appendComputePolicy(compute, "Mongos", c.Mongos)
+			disableScaleDown = c.DisableScaleDown
+		}
+		if s := a.Spec.Storage; s != nil {
+			storage = appendStoragePolicy(storage, "Standalone", s.Standalone)
+			storage = appendStoragePolicy(storage, "ReplicaSet", s.ReplicaSet)
+			storage = appendStoragePolicy(storage, "ConfigServer", s.ConfigServer)
+			storage = appendStoragePolicy(storage, "Shard", s.Shard)
+		}
+	default:
+		return getReplicaAutoscaling(item)
+	}
+
+	computeSection := Section{Title: "Compute"}
+	if len(compute) > 0 {
+		computeSection.addField("Disable Scale Down", "%v", disableScaleDown)
+		for _, p := range compute {
+			computeSection.addSection(getComputePolicy(p.component, p.spec))
+		}
+	}
+
+	storageSection := Section{Title: "Storage"}
+	if len(storage) > 0 {
+		storageSection.Table = newTable("Component", "Trigger", "Usage Threshold", "Scaling Threshold")
+		for _, p := range storage {
+			storageSection.Table.addRow(p.component, p.spec.Trigger, fmt.Sprintf("%d%%", p.spec.UsageThreshold), fmt.Sprintf("%d%%", p.spec.ScalingThreshold))
+		}
+	}
+
+	return []Section{computeSection, storageSection}
+}
+
+func appendComputePolicy(policies []computePolicy, component string, spec *autoscalingapi.ComputeAutoscalerSpec) []computePolicy {
+	if spec == nil {
+		return policies
+	}
+	return append(policies, computePolicy{component: component, spec: spec})
+}
+
+func appendStoragePolicy(policies []storagePolicy, component string, spec *autoscalingapi.StorageAutoscalerSpec) []storagePolicy {
+	if spec == nil {
+		return policies
+	}
+	return append(policies, storagePolicy{component: component, spec: spec})
+}
+
+func getComputePolicy(component string, spec *autoscalingapi.ComputeAutoscalerSpec) *Section {
+	section := &Section{Title: component}
+	section.addField("Trigger", "%s", spec.Trigger)
+	section.addField("Min Allowed", "%s", formatResourceList(spec.MinAllowed))
+	section.addField("Max Allowed", "%s", formatResourceList(spec.MaxAllowed))
+	if len(spec.ControlledResources) > 0 {
+		names := make([]string, 0, len(spec.ControlledResources))
+		for _, r := range spec.ControlledResources {
+			names = append(names, string(r))
+		}
+		section.addField("Controlled Resources", "%s", strings.Join(names, ", "))
+	}
+	if spec.ContainerControlledValues != nil {
+		section.addField("Container Controlled Values", "%s", *spec.ContainerControlledValues)
+	}
+	section.addField("Resource Diff Percentage", "%d%%", spec.ResourceDiffPercentage)
+	if spec.PodLifeTimeThreshold.Duration > 0 {
+		section.addField("Pod LifeTime Threshold", "%s", spec.PodLifeTimeThreshold.Duration)
+	}
+	if spec.InMemoryScalingThreshold > 0 {
+		section.addField("InMemory Scaling Threshold", "%d%%", spec.InMemoryScalingThreshold)
+	}
+	return section
+}
+
+func getReplicaAutoscaling(item *autoscaler) []Section {
+	replicas := Section{Title: "Replicas"}
+	replicas.addField("Min Replicas", "%s", formatInt32Ptr(item.Spec.MinReplicas))
+	replicas.addField("Max Replicas", "%d", item.Spec.MaxReplicas)
+
+	metrics := Section{Title: "Metrics"}
+	for _, m := range item.Spec.Metrics {
+		source, target := formatMetricSpec(m)
+		metrics.addField(source, "%s", target)
+	}
+	return []Section{replicas, metrics}
+}
+
+// formatMetricSpec returns the source of a metric and its target value.
+func formatMetricSpec(m autoscaling.MetricSpec) (string, string) {
+	switch {
+	case m.Type == autoscaling.ResourceMetricSourceType && m.Resource != nil:
+		return fmt.Sprintf("resource %s on pods", m.Resource.Name), formatMetricTarget(m.Resource.Target)
+	case m.Type == autoscaling.ContainerResourceMetricSourceType && m.ContainerResource != nil:
+		return fmt.Sprintf("resource %s of container %q on pods", m.ContainerResource.Name, m.ContainerResource.Container), formatMetricTarget(m.ContainerResource.Target)
+	case m.Type == autoscaling.PodsMetricSourceType && m.Pods != nil:
+		return fmt.Sprintf("%q on pods", m.Pods.Metric.Name), formatMetricTarget(m.Pods.Target)
+	case m.Type == autoscaling.ObjectMetricSourceType && m.Object != nil:
+		return fmt.Sprintf("%q on %s/%s", m.Object.Metric.Name, m.Object.DescribedObject.Kind, m.Object.DescribedObject.Name), formatMetricTarget(m.Object.Target)
+	case m.Type == autoscaling.ExternalMetricSourceType && m.External != nil:
+		return fmt.Sprintf("%q (external metric)", m.External.Metric.Name), formatMetricTarget(m.External.Target)
+	}
+	return fmt.Sprintf("<unknown metric type %q>", m.Type), ValueNone
+}
+
+func formatMetricTarget(t autoscaling.MetricTarget) string {
+	switch {
+	case t.Type == autoscaling.UtilizationMetricType && t.AverageUtilization != nil:
+		return fmt.Sprintf("%d%% (average utilization)", *t.AverageUtilization)
+	case t.Type == autoscaling.AverageValueMetricType && t.AverageValue != nil:
+		return fmt.Sprintf("%s (average value)", t.AverageValue.String())
+	case t.Type == autoscaling.ValueMetricType && t.Value != nil:
+		return t.Value.String()
+	}
+	return ValueNone
+}
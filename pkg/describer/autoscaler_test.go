@@ -0,0 +1,92 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"reflect"
+	"testing"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetDatabaseComponentsElasticsearchTiers(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+	cases := []struct {
+		name     string
+		topology *api.ElasticsearchClusterTopology
+		want     []string
+	}{
+		{
+			name: "combined",
+			want: []string{"Node"},
+		},
+		{
+			name: "master ingest data",
+			topology: &api.ElasticsearchClusterTopology{
+				Master: api.ElasticsearchNode{Replicas: replicas(3)},
+				Ingest: api.ElasticsearchNode{Replicas: replicas(2)},
+				Data:   &api.ElasticsearchNode{Replicas: replicas(2)},
+			},
+			want: []string{"Master", "Ingest", "Data"},
+		},
+		{
+			name: "all tiers",
+			topology: &api.ElasticsearchClusterTopology{
+				Master:       api.ElasticsearchNode{Replicas: replicas(3)},
+				Ingest:       api.ElasticsearchNode{Replicas: replicas(2)},
+				DataContent:  &api.ElasticsearchNode{Replicas: replicas(1)},
+				DataHot:      &api.ElasticsearchNode{Replicas: replicas(3)},
+				DataWarm:     &api.ElasticsearchNode{Replicas: replicas(2)},
+				DataCold:     &api.ElasticsearchNode{Replicas: replicas(1)},
+				DataFrozen:   &api.ElasticsearchNode{Replicas: replicas(1)},
+				ML:           &api.ElasticsearchNode{Replicas: replicas(1)},
+				Transform:    &api.ElasticsearchNode{Replicas: replicas(1)},
+				Coordinating: &api.ElasticsearchNode{Replicas: replicas(2)},
+			},
+			want: []string{"Master", "Ingest", "Data Content", "Data Hot", "Data Warm", "Data Cold", "Data Frozen", "ML", "Transform", "Coordinating"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := &api.Elasticsearch{
+				TypeMeta:   metav1.TypeMeta{APIVersion: api.SchemeGroupVersion.String(), Kind: api.ResourceKindElasticsearch},
+				ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: fixtureNamespace},
+				Spec:       api.ElasticsearchSpec{Replicas: replicas(1), Topology: c.topology},
+			}
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			components, err := getDatabaseComponents(&unstructured.Unstructured{Object: content})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, component := range components {
+				got = append(got, component.component)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got components %v, want %v", got, c.want)
+			}
+		})
+	}
+}
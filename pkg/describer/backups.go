@@ -18,7 +18,6 @@ package describer
 
 import (
 	"context"
-	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
@@ -32,20 +31,10 @@ const (
 	KindAppBinding string = "AppBinding"
 )
 
-type backupInvokerInfo struct {
-	name              string
-	kind              string
-	schedule          string
-	task              string
-	repository        string
-	bucket            string
-	creationTimestamp metav1.Time
-}
-
-func showBackups(stash stash.Interface, ab *appcat.AppBinding, w describe.PrefixWriter) error {
-	w.Write(LEVEL_0, "\n")
-	w.Write(LEVEL_0, "Backup:\n")
-	var invokers []backupInvokerInfo
+// getBackup returns the Stash backup invokers that target the given AppBinding
+// along with their BackupSessions.
+func getBackup(stash stash.Interface, ab *appcat.AppBinding) (*Backup, error) {
+	backup := &Backup{}
 	// There could be two types of backup invokers.
 	// 1. BackupConfiguration
 	// 2. BackupBatch
@@ -53,50 +42,68 @@ func showBackups(stash stash.Interface, ab *appcat.AppBinding, w describe.Prefix
 	// Get BackupConfiguration type invokers
 	bcInvokers, err := getBackupConfigurationTypeInvokers(stash, ab)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	invokers = append(invokers, bcInvokers...)
+	backup.Invokers = append(backup.Invokers, bcInvokers...)
 
 	// Get BackupBatch type invokers
 	bbInvokers, err := getBackupBatchTypeInvokers(stash, ab)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	backup.Invokers = append(backup.Invokers, bbInvokers...)
+
+	if len(backup.Invokers) == 0 {
+		return backup, nil
+	}
+
+	// Get the BackupSessions for the above invokers
+	backupSessions, err := getBackupSessions(stash, ab.Namespace, backup.Invokers)
+	if err != nil {
+		return nil, err
 	}
-	invokers = append(invokers, bbInvokers...)
+	for _, bs := range backupSessions {
+		backup.Sessions = append(backup.Sessions, BackupSession{
+			Name:              bs.Name,
+			InvokerKind:       bs.Spec.Invoker.Kind,
+			InvokerName:       bs.Spec.Invoker.Name,
+			Phase:             string(bs.Status.Phase),
+			CreationTimestamp: bs.CreationTimestamp,
+		})
+	}
+	return backup, nil
+}
 
-	if len(invokers) == 0 {
+func describeBackup(backup *Backup, w describe.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "Backup:\n")
+	if len(backup.Invokers) == 0 {
 		w.Write(LEVEL_1, "No backup has been configured.\n")
-		return nil
+		return
 	}
 	// Print the backup invokers table
 	w.Write(LEVEL_1, "Backup Invokers:\n")
 	w.Write(LEVEL_2, "Name\tKind\tSchedule\tTask\tRepository\tBucket\tAge\n")
 	w.Write(LEVEL_2, "----\t----\t--------\t----\t----------\t------\t---\n")
-	for _, invk := range invokers {
-		age := duration.HumanDuration(time.Since(invk.creationTimestamp.Time))
-		w.Write(LEVEL_2, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", invk.name, invk.kind, invk.schedule, invk.task, invk.repository, invk.bucket, age)
+	for _, invk := range backup.Invokers {
+		age := duration.HumanDuration(timeNow().Sub(invk.CreationTimestamp.Time))
+		w.Write(LEVEL_2, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", invk.Name, invk.Kind, invk.Schedule, invk.Task, invk.Repository, invk.Bucket, age)
 	}
 
-	// Get the BackupSessions for the above invokers
-	backupSessions, err := getBackupSessions(stash, ab.Namespace, invokers)
-	if err != nil {
-		return err
-	}
 	// Print recent backup table
-	if len(backupSessions) != 0 {
+	if len(backup.Sessions) != 0 {
 		w.Write(LEVEL_1, "Recent Backups:\n")
 		w.Write(LEVEL_2, "Name\tInvoker-kind\tInvoker-name\tPhase\tAge\n")
 		w.Write(LEVEL_2, "----\t------------\t------------\t-----\t---\n")
-		for _, bs := range backupSessions {
-			age := duration.HumanDuration(time.Since(bs.CreationTimestamp.Time))
-			w.Write(LEVEL_2, "%s\t%s\t%s\t%s\t%s\n", bs.Name, bs.Spec.Invoker.Kind, bs.Spec.Invoker.Name, bs.Status.Phase, age)
+		for _, bs := range backup.Sessions {
+			age := duration.HumanDuration(timeNow().Sub(bs.CreationTimestamp.Time))
+			w.Write(LEVEL_2, "%s\t%s\t%s\t%s\t%s\n", bs.Name, bs.InvokerKind, bs.InvokerName, bs.Phase, age)
 		}
 	}
-	return nil
 }
 
-func getBackupConfigurationTypeInvokers(stash stash.Interface, ab *appcat.AppBinding) ([]backupInvokerInfo, error) {
-	var bcInvokers []backupInvokerInfo
+func getBackupConfigurationTypeInvokers(stash stash.Interface, ab *appcat.AppBinding) ([]BackupInvoker, error) {
+	var bcInvokers []BackupInvoker
 	backupConfigurations, err := stash.StashV1beta1().BackupConfigurations(ab.Namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -107,19 +114,19 @@ func getBackupConfigurationTypeInvokers(stash stash.Interface, ab *appcat.AppBin
 		if bc.Spec.Target != nil &&
 			bc.Spec.Target.Ref.Kind == KindAppBinding &&
 			bc.Spec.Target.Ref.Name == ab.Name {
-			invoker := backupInvokerInfo{
-				name:              bc.Name,
-				kind:              bc.Kind,
-				schedule:          bc.Spec.Schedule,
-				task:              bc.Spec.Task.Name,
-				repository:        bc.Spec.Repository.Name,
-				creationTimestamp: bc.CreationTimestamp,
+			invoker := BackupInvoker{
+				Name:              bc.Name,
+				Kind:              bc.Kind,
+				Schedule:          bc.Spec.Schedule,
+				Task:              bc.Spec.Task.Name,
+				Repository:        bc.Spec.Repository.Name,
+				CreationTimestamp: bc.CreationTimestamp,
 			}
 			bucket, err := getBucket(stash, bc.Spec.Repository.Name, bc.Namespace)
 			if err != nil {
 				return nil, err
 			}
-			invoker.bucket = bucket
+			invoker.Bucket = bucket
 
 			bcInvokers = append(bcInvokers, invoker)
 		}
@@ -127,8 +134,8 @@ func getBackupConfigurationTypeInvokers(stash stash.Interface, ab *appcat.AppBin
 	return bcInvokers, nil
 }
 
-func getBackupBatchTypeInvokers(stash stash.Interface, ab *appcat.AppBinding) ([]backupInvokerInfo, error) {
-	var bbInvokers []backupInvokerInfo
+func getBackupBatchTypeInvokers(stash stash.Interface, ab *appcat.AppBinding) ([]BackupInvoker, error) {
+	var bbInvokers []BackupInvoker
 	backupBatches, err := stash.StashV1beta1().BackupBatches(ab.Namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -138,19 +145,19 @@ func getBackupBatchTypeInvokers(stash stash.Interface, ab *appcat.AppBinding) ([
 			if m.Target != nil &&
 				m.Target.Ref.Kind == KindAppBinding &&
 				m.Target.Ref.Name == ab.Name {
-				invoker := backupInvokerInfo{
-					name:              bb.Name,
-					kind:              bb.Kind,
-					schedule:          bb.Spec.Schedule,
-					task:              m.Task.Name,
-					repository:        bb.Spec.Repository.Name,
-					creationTimestamp: bb.CreationTimestamp,
+				invoker := BackupInvoker{
+					Name:              bb.Name,
+					Kind:              bb.Kind,
+					Schedule:          bb.Spec.Schedule,
+					Task:              m.Task.Name,
+					Repository:        bb.Spec.Repository.Name,
+					CreationTimestamp: bb.CreationTimestamp,
 				}
 				bucket, err := getBucket(stash, bb.Spec.Repository.Name, bb.Namespace)
 				if err != nil {
 					return nil, err
 				}
-				invoker.bucket = bucket
+				invoker.Bucket = bucket
 
 				bbInvokers = append(bbInvokers, invoker)
 			}
@@ -169,7 +176,7 @@ func getBucket(stash stash.Interface, repoName, repoNamespace string) (string, e
 	return repo.Spec.Backend.Container()
 }
 
-func getBackupSessions(stash stash.Interface, namespace string, invokers []backupInvokerInfo) ([]stashV1beta1.BackupSession, error) {
+func getBackupSessions(stash stash.Interface, namespace string, invokers []BackupInvoker) ([]stashV1beta1.BackupSession, error) {
 	var backupSessions []stashV1beta1.BackupSession
 
 	bsList, err := stash.StashV1beta1().BackupSessions(namespace).List(context.TODO(), metav1.ListOptions{})
@@ -185,10 +192,10 @@ func getBackupSessions(stash stash.Interface, namespace string, invokers []backu
 	return backupSessions, nil
 }
 
-func ownByInvoker(bs stashV1beta1.BackupSession, invokers []backupInvokerInfo) bool {
+func ownByInvoker(bs stashV1beta1.BackupSession, invokers []BackupInvoker) bool {
 	for i := range invokers {
-		if invokers[i].kind == bs.Spec.Invoker.Kind &&
-			invokers[i].name == bs.Spec.Invoker.Name {
+		if invokers[i].Kind == bs.Spec.Invoker.Kind &&
+			invokers[i].Name == bs.Spec.Invoker.Name {
 			return true
 		}
 	}
@@ -0,0 +1,570 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+	"sync"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	appsclient "k8s.io/client-go/kubernetes/typed/apps/v1"
+	coreclient "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/reference"
+	kmdiscovery "kmodules.xyz/client-go/discovery"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+	stash "stash.appscode.dev/apimachinery/client/clientset/versioned"
+	stashclient "stash.appscode.dev/apimachinery/client/clientset/versioned/typed/stash/v1beta1"
+)
+
+// The describers list the pods, services, events and Stash objects of the
+// namespace of every object they describe, and check for the Stash CRDs each
+// time. clientCache wraps the clients so that every namespace is listed only
+// once per run, with all the lists of a namespace fetched concurrently the
+// first time it is seen, and the discovery information is read only once.
+//
+// The wrappers only serve plain List calls, filtered by a label selector, from
+// the cache. Calls with any other option go to the API server.
+type clientCache struct {
+	kube      kubernetes.Interface
+	stash     stash.Interface
+	dynamic   dynamic.Interface
+	discovery discovery.CachedDiscoveryInterface
+
+	mu         sync.Mutex
+	namespaces map[string]*namespaceCache
+	resources  map[resourceKey]*lazyList
+}
+
+// namespaceCache holds the lists of the objects of a single namespace.
+type namespaceCache struct {
+	pods                 lazyList
+	services             lazyList
+	endpoints            lazyList
+	events               lazyList
+	statefulSets         lazyList
+	deployments          lazyList
+	backupConfigurations lazyList
+	backupBatches        lazyList
+	backupSessions       lazyList
+}
+
+type resourceKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+// lazyList lists a resource on first use and shares the result with all the
+// callers, including the ones waiting for the first call to finish.
+type lazyList struct {
+	once sync.Once
+	list func() (runtime.Object, error)
+	obj  runtime.Object
+	err  error
+}
+
+func (l *lazyList) get() (runtime.Object, error) {
+	l.once.Do(func() {
+		l.obj, l.err = l.list()
+	})
+	return l.obj, l.err
+}
+
+func newClientCache(kube kubernetes.Interface, s stash.Interface, dc dynamic.Interface) *clientCache {
+	return &clientCache{
+		kube:       kube,
+		stash:      s,
+		dynamic:    dc,
+		discovery:  memory.NewMemCacheClient(kube.Discovery()),
+		namespaces: map[string]*namespaceCache{},
+		resources:  map[resourceKey]*lazyList{},
+	}
+}
+
+// kubeClient returns a kubernetes client that serves pods, services,
+// endpoints, events, StatefulSets, Deployments and discovery from the cache.
+func (c *clientCache) kubeClient() kubernetes.Interface {
+	return &cachedKubeClient{Interface: c.kube, cache: c}
+}
+
+// stashClient returns a Stash client that serves BackupConfigurations,
+// BackupBatches and BackupSessions from the cache.
+func (c *clientCache) stashClient() stash.Interface {
+	return &cachedStashClient{Interface: c.stash, cache: c}
+}
+
+// dynamicClient returns a dynamic client that serves the lists of every
+// namespaced resource from the cache.
+func (c *clientCache) dynamicClient() dynamic.Interface {
+	return &cachedDynamicClient{Interface: c.dynamic, cache: c}
+}
+
+// namespace returns the cache of the given namespace, and starts listing all
+// of its objects in the background the first time it is called.
+func (c *clientCache) namespace(ns string) *namespaceCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if nc, ok := c.namespaces[ns]; ok {
+		return nc
+	}
+
+	ctx := context.TODO()
+	all := metav1.ListOptions{}
+	nc := &namespaceCache{}
+	nc.pods.list = func() (runtime.Object, error) {
+		return c.kube.CoreV1().Pods(ns).List(ctx, all)
+	}
+	nc.services.list = func() (runtime.Object, error) {
+		return c.kube.CoreV1().Services(ns).List(ctx, all)
+	}
+	nc.endpoints.list = func() (runtime.Object, error) {
+		return c.kube.CoreV1().Endpoints(ns).List(ctx, all)
+	}
+	nc.events.list = func() (runtime.Object, error) {
+		return c.kube.CoreV1().Events(ns).List(ctx, all)
+	}
+	nc.statefulSets.list = func() (runtime.Object, error) {
+		return c.kube.AppsV1().StatefulSets(ns).List(ctx, all)
+	}
+	nc.deployments.list = func() (runtime.Object, error) {
+		return c.kube.AppsV1().Deployments(ns).List(ctx, all)
+	}
+	nc.backupConfigurations.list = func() (runtime.Object, error) {
+		return c.stash.StashV1beta1().BackupConfigurations(ns).List(ctx, all)
+	}
+	nc.backupBatches.list = func() (runtime.Object, error) {
+		return c.stash.StashV1beta1().BackupBatches(ns).List(ctx, all)
+	}
+	nc.backupSessions.list = func() (runtime.Object, error) {
+		return c.stash.StashV1beta1().BackupSessions(ns).List(ctx, all)
+	}
+	c.namespaces[ns] = nc
+
+	prefetch := []*lazyList{&nc.pods, &nc.services, &nc.endpoints, &nc.events, &nc.statefulSets, &nc.deployments}
+	if kmdiscovery.ExistsGroupKind(c.discovery, stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		prefetch = append(prefetch, &nc.backupConfigurations, &nc.backupBatches, &nc.backupSessions)
+	}
+	for _, l := range prefetch {
+		go l.get()
+	}
+	return nc
+}
+
+// resource returns the cached list of a resource read through the dynamic
+// client.
+func (c *clientCache) resource(gvr schema.GroupVersionResource, ns string) *lazyList {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := resourceKey{gvr: gvr, namespace: ns}
+	if l, ok := c.resources[key]; ok {
+		return l
+	}
+	l := &lazyList{list: func() (runtime.Object, error) {
+		return c.dynamic.Resource(gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{})
+	}}
+	c.resources[key] = l
+	return l
+}
+
+// cacheable reports whether a List call with the given options can be served
+// from the cache, and returns the parsed label selector if so.
+func cacheable(ns string, opts metav1.ListOptions) (labels.Selector, bool) {
+	if ns == metav1.NamespaceAll ||
+		opts.FieldSelector != "" ||
+		opts.ResourceVersion != "" ||
+		opts.Limit != 0 ||
+		opts.Continue != "" ||
+		opts.Watch {
+		return nil, false
+	}
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, false
+	}
+	return selector, true
+}
+
+type cachedKubeClient struct {
+	kubernetes.Interface
+	cache *clientCache
+}
+
+func (c *cachedKubeClient) CoreV1() coreclient.CoreV1Interface {
+	return &cachedCoreClient{CoreV1Interface: c.Interface.CoreV1(), cache: c.cache}
+}
+
+func (c *cachedKubeClient) AppsV1() appsclient.AppsV1Interface {
+	return &cachedAppsClient{AppsV1Interface: c.Interface.AppsV1(), cache: c.cache}
+}
+
+func (c *cachedKubeClient) Discovery() discovery.DiscoveryInterface {
+	return c.cache.discovery
+}
+
+type cachedCoreClient struct {
+	coreclient.CoreV1Interface
+	cache *clientCache
+}
+
+func (c *cachedCoreClient) Pods(namespace string) coreclient.PodInterface {
+	return &cachedPods{PodInterface: c.CoreV1Interface.Pods(namespace), cache: c.cache, namespace: namespace}
+}
+
+func (c *cachedCoreClient) Services(namespace string) coreclient.ServiceInterface {
+	return &cachedServices{ServiceInterface: c.CoreV1Interface.Services(namespace), cache: c.cache, namespace: namespace}
+}
+
+func (c *cachedCoreClient) Endpoints(namespace string) coreclient.EndpointsInterface {
+	return &cachedEndpoints{EndpointsInterface: c.CoreV1Interface.Endpoints(namespace), cache: c.cache, namespace: namespace}
+}
+
+func (c *cachedCoreClient) Events(namespace string) coreclient.EventInterface {
+	return &cachedEvents{EventInterface: c.CoreV1Interface.Events(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedPods struct {
+	coreclient.PodInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedPods) List(ctx context.Context, opts metav1.ListOptions) (*core.PodList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.PodInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).pods.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*core.PodList)
+	result := &core.PodList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+type cachedServices struct {
+	coreclient.ServiceInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedServices) List(ctx context.Context, opts metav1.ListOptions) (*core.ServiceList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.ServiceInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).services.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*core.ServiceList)
+	result := &core.ServiceList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+type cachedEndpoints struct {
+	coreclient.EndpointsInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedEndpoints) Get(ctx context.Context, name string, opts metav1.GetOptions) (*core.Endpoints, error) {
+	if opts.ResourceVersion != "" {
+		return c.EndpointsInterface.Get(ctx, name, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).endpoints.get()
+	if err != nil {
+		return &core.Endpoints{}, err
+	}
+	for _, item := range obj.(*core.EndpointsList).Items {
+		if item.Name == name {
+			return item.DeepCopy(), nil
+		}
+	}
+	return &core.Endpoints{}, kerr.NewNotFound(core.Resource("endpoints"), name)
+}
+
+type cachedEvents struct {
+	coreclient.EventInterface
+	cache     *clientCache
+	namespace string
+}
+
+// Search returns the events of the given object, matching them the same way as
+// the field selector used by the typed client.
+func (c *cachedEvents) Search(scheme *runtime.Scheme, objOrRef runtime.Object) (*core.EventList, error) {
+	ref, err := reference.GetReference(scheme, objOrRef)
+	if err != nil || c.namespace == metav1.NamespaceAll || ref.Namespace != c.namespace {
+		return c.EventInterface.Search(scheme, objOrRef)
+	}
+	obj, err := c.cache.namespace(c.namespace).events.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*core.EventList)
+	result := &core.EventList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		involved := item.InvolvedObject
+		if involved.Name != ref.Name || involved.Namespace != ref.Namespace {
+			continue
+		}
+		if ref.Kind != "" && involved.Kind != ref.Kind {
+			continue
+		}
+		if ref.UID != "" && involved.UID != ref.UID {
+			continue
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+type cachedAppsClient struct {
+	appsclient.AppsV1Interface
+	cache *clientCache
+}
+
+func (c *cachedAppsClient) StatefulSets(namespace string) appsclient.StatefulSetInterface {
+	return &cachedStatefulSets{StatefulSetInterface: c.AppsV1Interface.StatefulSets(namespace), cache: c.cache, namespace: namespace}
+}
+
+func (c *cachedAppsClient) Deployments(namespace string) appsclient.DeploymentInterface {
+	return &cachedDeployments{DeploymentInterface: c.AppsV1Interface.Deployments(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedStatefulSets struct {
+	appsclient.StatefulSetInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedStatefulSets) List(ctx context.Context, opts metav1.ListOptions) (*apps.StatefulSetList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.StatefulSetInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).statefulSets.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*apps.StatefulSetList)
+	result := &apps.StatefulSetList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+type cachedDeployments struct {
+	appsclient.DeploymentInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedDeployments) List(ctx context.Context, opts metav1.ListOptions) (*apps.DeploymentList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.DeploymentInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).deployments.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*apps.DeploymentList)
+	result := &apps.DeploymentList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+type cachedStashClient struct {
+	stash.Interface
+	cache *clientCache
+}
+
+func (c *cachedStashClient) StashV1beta1() stashclient.StashV1beta1Interface {
+	return &cachedStashV1beta1Client{StashV1beta1Interface: c.Interface.StashV1beta1(), cache: c.cache}
+}
+
+type cachedStashV1beta1Client struct {
+	stashclient.StashV1beta1Interface
+	cache *clientCache
+}
+
+func (c *cachedStashV1beta1Client) BackupConfigurations(namespace string) stashclient.BackupConfigurationInterface {
+	return &cachedBackupConfigurations{BackupConfigurationInterface: c.StashV1beta1Interface.BackupConfigurations(namespace), cache: c.cache, namespace: namespace}
+}
+
+func (c *cachedStashV1beta1Client) BackupBatches(namespace string) stashclient.BackupBatchInterface {
+	return &cachedBackupBatches{BackupBatchInterface: c.StashV1beta1Interface.BackupBatches(namespace), cache: c.cache, namespace: namespace}
+}
+
+func (c *cachedStashV1beta1Client) BackupSessions(namespace string) stashclient.BackupSessionInterface {
+	return &cachedBackupSessions{BackupSessionInterface: c.StashV1beta1Interface.BackupSessions(namespace), cache: c.cache, namespace: namespace}
+}
+
+type cachedBackupConfigurations struct {
+	stashclient.BackupConfigurationInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedBackupConfigurations) List(ctx context.Context, opts metav1.ListOptions) (*stashV1beta1.BackupConfigurationList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.BackupConfigurationInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).backupConfigurations.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*stashV1beta1.BackupConfigurationList)
+	result := &stashV1beta1.BackupConfigurationList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+type cachedBackupBatches struct {
+	stashclient.BackupBatchInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedBackupBatches) List(ctx context.Context, opts metav1.ListOptions) (*stashV1beta1.BackupBatchList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.BackupBatchInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).backupBatches.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*stashV1beta1.BackupBatchList)
+	result := &stashV1beta1.BackupBatchList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+type cachedBackupSessions struct {
+	stashclient.BackupSessionInterface
+	cache     *clientCache
+	namespace string
+}
+
+func (c *cachedBackupSessions) List(ctx context.Context, opts metav1.ListOptions) (*stashV1beta1.BackupSessionList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.BackupSessionInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.namespace(c.namespace).backupSessions.get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*stashV1beta1.BackupSessionList)
+	result := &stashV1beta1.BackupSessionList{TypeMeta: list.TypeMeta, ListMeta: list.ListMeta}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+type cachedDynamicClient struct {
+	dynamic.Interface
+	cache *clientCache
+}
+
+func (c *cachedDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &cachedDynamicResource{NamespaceableResourceInterface: c.Interface.Resource(gvr), cache: c.cache, gvr: gvr}
+}
+
+type cachedDynamicResource struct {
+	dynamic.NamespaceableResourceInterface
+	cache *clientCache
+	gvr   schema.GroupVersionResource
+}
+
+func (c *cachedDynamicResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &cachedDynamicNamespacedResource{
+		ResourceInterface: c.NamespaceableResourceInterface.Namespace(namespace),
+		cache:             c.cache,
+		gvr:               c.gvr,
+		namespace:         namespace,
+	}
+}
+
+type cachedDynamicNamespacedResource struct {
+	dynamic.ResourceInterface
+	cache     *clientCache
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+func (c *cachedDynamicNamespacedResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	selector, ok := cacheable(c.namespace, opts)
+	if !ok {
+		return c.ResourceInterface.List(ctx, opts)
+	}
+	obj, err := c.cache.resource(c.gvr, c.namespace).get()
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*unstructured.UnstructuredList)
+	result := &unstructured.UnstructuredList{Object: list.Object}
+	for _, item := range list.Items {
+		if selector.Matches(labels.Set(item.GetLabels())) {
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
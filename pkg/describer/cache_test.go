@@ -0,0 +1,68 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"testing"
+	"time"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubectl/pkg/describe"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+)
+
+func TestClientCacheSharesNamespaceLists(t *testing.T) {
+	now := timeNow
+	timeNow = func() time.Time { return fixtureNow }
+	defer func() { timeNow = now }()
+
+	pg := postgresFixture(true)
+	mysql := mysqlFixture(true)
+	// describe both databases from a single cluster
+	c := pg.cluster
+	c.add(mysql.cluster.objects...)
+	for gvr, items := range mysql.cluster.resources {
+		c.resources[gvr] = append(c.resources[gvr], items...)
+	}
+	describers := c.newDescribers()
+
+	for _, f := range []fixture{pg, mysql, pg} {
+		d := describers[api.Kind(f.kind)]
+		if _, err := d.Describe(fixtureNamespace, f.db, describe.DescriberSettings{ShowEvents: true}); err != nil {
+			t.Fatalf("failed to describe %s %s: %v", f.kind, f.db, err)
+		}
+	}
+
+	for _, proto := range []runtime.Object{
+		&core.Pod{},
+		&core.Service{},
+		&core.Endpoints{},
+		&core.Event{},
+		&apps.StatefulSet{},
+		&stashV1beta1.BackupConfiguration{},
+		&stashV1beta1.BackupBatch{},
+		&stashV1beta1.BackupSession{},
+	} {
+		if n := c.listCalls(proto); n != 1 {
+			t.Errorf("%T listed %d times, expected once", proto, n)
+		}
+	}
+}
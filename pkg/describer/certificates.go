@@ -0,0 +1,218 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/describe"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+// DefaultCertificateExpiryWindow is how long before its expiry a certificate
+// is reported as expiring by default.
+const DefaultCertificateExpiryWindow = 30 * 24 * time.Hour
+
+// GetCertificates parses the certificates stored in the secrets of a TLS
+// configuration. The secret of a certificate whose secret name is not set is
+// looked up by the name the operator gives it, i.e. <db>-<alias>-cert.
+func GetCertificates(client kubernetes.Interface, namespace, dbName string, tls *kmapi.TLSConfig, window time.Duration) []Certificate {
+	if tls == nil {
+		return nil
+	}
+
+	certs := make([]Certificate, 0, len(tls.Certificates))
+	for _, spec := range tls.Certificates {
+		secretName := certificateSecretName(dbName, spec)
+		cert := Certificate{
+			Alias:      spec.Alias,
+			SecretName: secretName,
+		}
+
+		secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			cert.addWarning("Secret %s not found.", secretName)
+		} else if err != nil {
+			cert.addWarning("Failed to get secret %s: %v", secretName, err)
+		} else {
+			inspectCertificate(&cert, secret, window)
+		}
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+// inspectCertificate fills in the certificate from the PEM data of a TLS
+// secret and verifies its chain against the CA in the same secret.
+func inspectCertificate(cert *Certificate, secret *core.Secret, window time.Duration) {
+	chain, err := parseCertificates(secret.Data[core.TLSCertKey])
+	if err != nil {
+		cert.addWarning("Failed to parse %s: %v", core.TLSCertKey, err)
+		return
+	}
+	if len(chain) == 0 {
+		cert.addWarning("No certificate found in %s.", core.TLSCertKey)
+		return
+	}
+
+	leaf := chain[0]
+	cert.Subject = leaf.Subject.String()
+	cert.SANs = getSANs(leaf)
+	cert.Issuer = leaf.Issuer.String()
+	cert.NotBefore = &metav1.Time{Time: leaf.NotBefore}
+	cert.NotAfter = &metav1.Time{Time: leaf.NotAfter}
+	cert.KeyType = getKeyType(leaf)
+
+	now := timeNow()
+	if left := leaf.NotAfter.Sub(now); left <= 0 {
+		cert.addWarning("Expired %d days ago.", int(-left.Hours()/24))
+	} else if left <= window {
+		cert.addWarning("Expires in %d days.", int(left.Hours()/24))
+	}
+
+	cas, err := parseCertificates(secret.Data[caCertKey])
+	if err != nil {
+		cert.addWarning("Failed to parse %s: %v", caCertKey, err)
+		return
+	}
+	if len(cas) == 0 {
+		cert.addWarning("No CA found in %s to verify the chain against.", caCertKey)
+		return
+	}
+
+	roots := x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AddCert(ca)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		cert.addWarning("Chain does not verify against %s: %v", caCertKey, err)
+		return
+	}
+	cert.Verified = true
+}
+
+// caCertKey is the key of the CA certificate in the secrets issued by
+// cert-manager.
+const caCertKey = "ca.crt"
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+}
+
+func getSANs(c *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, c.EmailAddresses...)
+	for _, uri := range c.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func getKeyType(c *x509.Certificate) string {
+	switch key := c.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return c.PublicKeyAlgorithm.String()
+	}
+}
+
+func (c *Certificate) addWarning(format string, args ...interface{}) {
+	c.Warnings = append(c.Warnings, fmt.Sprintf(format, args...))
+}
+
+// RenderCertificates renders the certificates of a database in the same format
+// as the TLS certificates of its description.
+func RenderCertificates(kind, namespace, name string, certs []Certificate) (string, error) {
+	return tabbedString(func(out io.Writer) error {
+		w := describe.NewPrefixWriter(out)
+		w.Write(LEVEL_0, "Name:\t%s\n", name)
+		w.Write(LEVEL_0, "Namespace:\t%s\n", namespace)
+		w.Write(LEVEL_0, "Kind:\t%s\n", kind)
+		describeCertificates(certs, w)
+		return nil
+	})
+}
+
+func describeCertificates(certs []Certificate, w describe.PrefixWriter) {
+	section := Section{Title: "TLS Certificates"}
+	for _, c := range certs {
+		s := &Section{Title: c.Alias}
+		s.addField("Secret", "%s", c.SecretName)
+		if c.NotAfter != nil {
+			s.addField("Subject", "%s", c.Subject)
+			s.addField("SANs", "%s", strings.Join(c.SANs, ", "))
+			s.addField("Issuer", "%s", c.Issuer)
+			s.addField("Not Before", "%s", timeToString(c.NotBefore))
+			s.addField("Not After", "%s", timeToString(c.NotAfter))
+			s.addField("Key Type", "%s", c.KeyType)
+			s.addField("Chain Verified", "%v", c.Verified)
+		}
+		for _, warning := range c.Warnings {
+			s.addNote("Warning: %s", warning)
+		}
+		section.addSection(s)
+	}
+	w.Write(LEVEL_0, "\n")
+	describeSection(LEVEL_0, &section, w)
+}
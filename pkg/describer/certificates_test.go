@@ -0,0 +1,289 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+// testCA is a self-signed CA that issues the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             fixtureNow.Add(-365 * 24 * time.Hour),
+		NotAfter:              fixtureNow.Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns the PEM of a server certificate signed by the CA that is
+// valid from notBefore to notAfter.
+func (ca *testCA) issue(t *testing.T, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "pg", Organization: []string{"kubedb"}},
+		DNSNames:     []string{"pg.demo.svc"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func tlsSecret(name string, crt, ca []byte) *core.Secret {
+	data := map[string][]byte{core.TLSCertKey: crt}
+	if ca != nil {
+		data[caCertKey] = ca
+	}
+	return &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: fixtureNamespace},
+		Type:       core.SecretTypeTLS,
+		Data:       data,
+	}
+}
+
+func TestGetCertificates(t *testing.T) {
+	timeNow = func() time.Time { return fixtureNow }
+	defer func() { timeNow = time.Now }()
+
+	ca := newTestCA(t, "kubedb-ca")
+	otherCA := newTestCA(t, "other-ca")
+	day := 24 * time.Hour
+	valid := ca.issue(t, fixtureNow.Add(-day), fixtureNow.Add(365*day))
+	expiring := ca.issue(t, fixtureNow.Add(-day), fixtureNow.Add(10*day+time.Hour))
+	expired := ca.issue(t, fixtureNow.Add(-100*day), fixtureNow.Add(-5*day-time.Hour))
+	badPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not a certificate")})
+
+	cases := []struct {
+		name         string
+		secret       *core.Secret
+		secretName   string
+		window       time.Duration
+		wantSecret   string
+		wantParsed   bool
+		wantVerified bool
+		wantWarnings []string
+	}{
+		{
+			name:         "valid",
+			secret:       tlsSecret("pg-server-cert", valid, ca.pem),
+			wantSecret:   "pg-server-cert",
+			wantParsed:   true,
+			wantVerified: true,
+		},
+		{
+			name:         "custom secret name",
+			secret:       tlsSecret("pg-tls", valid, ca.pem),
+			secretName:   "pg-tls",
+			wantSecret:   "pg-tls",
+			wantParsed:   true,
+			wantVerified: true,
+		},
+		{
+			name:         "expiring",
+			secret:       tlsSecret("pg-server-cert", expiring, ca.pem),
+			wantSecret:   "pg-server-cert",
+			wantParsed:   true,
+			wantVerified: true,
+			wantWarnings: []string{"Expires in 10 days."},
+		},
+		{
+			name:         "expiring outside a shorter window",
+			secret:       tlsSecret("pg-server-cert", expiring, ca.pem),
+			window:       7 * day,
+			wantSecret:   "pg-server-cert",
+			wantParsed:   true,
+			wantVerified: true,
+		},
+		{
+			name:         "expired",
+			secret:       tlsSecret("pg-server-cert", expired, ca.pem),
+			wantSecret:   "pg-server-cert",
+			wantParsed:   true,
+			wantWarnings: []string{"Expired 5 days ago.", "Chain does not verify against ca.crt: "},
+		},
+		{
+			name:         "wrong CA",
+			secret:       tlsSecret("pg-server-cert", valid, otherCA.pem),
+			wantSecret:   "pg-server-cert",
+			wantParsed:   true,
+			wantWarnings: []string{"Chain does not verify against ca.crt: "},
+		},
+		{
+			name:         "no CA",
+			secret:       tlsSecret("pg-server-cert", valid, nil),
+			wantSecret:   "pg-server-cert",
+			wantParsed:   true,
+			wantWarnings: []string{"No CA found in ca.crt to verify the chain against."},
+		},
+		{
+			name:         "bad PEM",
+			secret:       tlsSecret("pg-server-cert", badPEM, ca.pem),
+			wantSecret:   "pg-server-cert",
+			wantWarnings: []string{"Failed to parse tls.crt: "},
+		},
+		{
+			name:         "no certificate",
+			secret:       tlsSecret("pg-server-cert", []byte("garbage"), ca.pem),
+			wantSecret:   "pg-server-cert",
+			wantWarnings: []string{"No certificate found in tls.crt."},
+		},
+		{
+			name:         "missing secret",
+			wantSecret:   "pg-server-cert",
+			wantWarnings: []string{"Secret pg-server-cert not found."},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := &fakeCluster{}
+			if c.secret != nil {
+				cluster.add(c.secret)
+			}
+			window := c.window
+			if window == 0 {
+				window = DefaultCertificateExpiryWindow
+			}
+			tls := &kmapi.TLSConfig{
+				Certificates: []kmapi.CertificateSpec{{Alias: "server", SecretName: c.secretName}},
+			}
+
+			certs := GetCertificates(fakeKube{c: cluster}, fixtureNamespace, "pg", tls, window)
+			if len(certs) != 1 {
+				t.Fatalf("got %d certificates, want 1", len(certs))
+			}
+			cert := certs[0]
+			if cert.Alias != "server" || cert.SecretName != c.wantSecret {
+				t.Errorf("got certificate %s in secret %s, want server in secret %s", cert.Alias, cert.SecretName, c.wantSecret)
+			}
+			if parsed := cert.NotAfter != nil; parsed != c.wantParsed {
+				t.Errorf("got parsed %v, want %v", parsed, c.wantParsed)
+			}
+			if cert.Verified != c.wantVerified {
+				t.Errorf("got verified %v, want %v", cert.Verified, c.wantVerified)
+			}
+			if len(cert.Warnings) != len(c.wantWarnings) {
+				t.Fatalf("got warnings %q, want %q", cert.Warnings, c.wantWarnings)
+			}
+			for i, want := range c.wantWarnings {
+				if !strings.HasPrefix(cert.Warnings[i], want) {
+					t.Errorf("got warning %q, want prefix %q", cert.Warnings[i], want)
+				}
+			}
+
+			if c.wantParsed {
+				if cert.Subject != "CN=pg,O=kubedb" || cert.Issuer != "CN=kubedb-ca" {
+					t.Errorf("got subject %q issued by %q", cert.Subject, cert.Issuer)
+				}
+				if !reflect.DeepEqual(cert.SANs, []string{"pg.demo.svc"}) {
+					t.Errorf("got SANs %v", cert.SANs)
+				}
+				if cert.KeyType != "ECDSA P-256" {
+					t.Errorf("got key type %q, want ECDSA P-256", cert.KeyType)
+				}
+			}
+		})
+	}
+}
+
+func TestGetCertificatesWithoutTLS(t *testing.T) {
+	if certs := GetCertificates(fakeKube{c: &fakeCluster{}}, fixtureNamespace, "pg", nil, DefaultCertificateExpiryWindow); certs != nil {
+		t.Errorf("got certificates %v without a TLS config", certs)
+	}
+}
+
+func TestParseCertificates(t *testing.T) {
+	ca := newTestCA(t, "kubedb-ca")
+	leaf := ca.issue(t, fixtureNow, fixtureNow.Add(time.Hour))
+	key := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")})
+
+	cases := []struct {
+		name    string
+		data    []byte
+		want    int
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "single", data: leaf, want: 1},
+		{name: "chain", data: append(append([]byte{}, leaf...), ca.pem...), want: 2},
+		{name: "other blocks are skipped", data: append(append([]byte{}, key...), leaf...), want: 1},
+		{name: "bad certificate", data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")}), wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			certs, err := parseCertificates(c.data)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if len(certs) != c.want {
+				t.Errorf("got %d certificates, want %d", len(certs), c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	autoscalingapi "kubedb.dev/apimachinery/apis/autoscaling/v1alpha1"
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	opsapi "kubedb.dev/apimachinery/apis/ops/v1alpha1"
+
+	"gomodules.xyz/pointer"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubectl/pkg/describe"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+	mona "kmodules.xyz/monitoring-agent-api/api/v1"
+	store "kmodules.xyz/objectstore-api/api/v1"
+	stashV1alpha1 "stash.appscode.dev/apimachinery/apis/stash/v1alpha1"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// fixture is a database object along with the cluster it is described in.
+// The describe output is compared with testdata/<name>.golden.
+type fixture struct {
+	name    string
+	kind    string
+	db      string
+	cluster *fakeCluster
+}
+
+const fixtureNamespace = "demo"
+
+var (
+	// fixtureNow is the clock the fixtures are described at.
+	fixtureNow     = time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	fixtureCreated = metav1.NewTime(fixtureNow.Add(-72 * time.Hour))
+	fixtureStarted = metav1.NewTime(fixtureNow.Add(-71 * time.Hour))
+)
+
+func TestDescribe(t *testing.T) {
+	timeNow = func() time.Time { return fixtureNow }
+	defer func() { timeNow = time.Now }()
+
+	for _, f := range fixtures() {
+		f := f
+		t.Run(f.name, func(t *testing.T) {
+			d, ok := f.cluster.newDescribers()[api.Kind(f.kind)]
+			if !ok {
+				t.Fatalf("no describer for %s", f.kind)
+			}
+			got, err := d.Describe(fixtureNamespace, f.db, describe.DescriberSettings{ShowEvents: true})
+			if err != nil {
+				t.Fatalf("failed to describe %s %s: %v", f.kind, f.db, err)
+			}
+			checkGolden(t, filepath.Join("testdata", f.name+".golden"), got)
+		})
+	}
+}
+
+// TestDescribeObject checks the structured description that describe prints
+// with -o json or -o yaml.
+func TestDescribeObject(t *testing.T) {
+	timeNow = func() time.Time { return fixtureNow }
+	defer func() { timeNow = time.Now }()
+
+	for _, f := range fixtures() {
+		f := f
+		t.Run(f.name, func(t *testing.T) {
+			d, ok := f.cluster.newDescribers()[api.Kind(f.kind)].(ObjectDescriber)
+			if !ok {
+				t.Fatalf("no object describer for %s", f.kind)
+			}
+			desc, err := d.DescribeObject(fixtureNamespace, f.db, describe.DescriberSettings{ShowEvents: true})
+			if err != nil {
+				t.Fatalf("failed to describe %s %s: %v", f.kind, f.db, err)
+			}
+			got, err := json.MarshalIndent(desc, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, filepath.Join("testdata", f.name+".json.golden"), string(got)+"\n")
+		})
+	}
+}
+
+// checkGolden compares got with the golden file, or writes it to the golden
+// file when the tests run with -update.
+func checkGolden(t *testing.T, golden, got string) {
+	t.Helper()
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v, run the tests with -update to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s, run the tests with -update to accept it\n%s", golden, diffLines(string(want), got))
+	}
+}
+
+// diffLines reports the first line where got differs from want.
+func diffLines(want, got string) string {
+	wl, gl := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n- %q\n+ %q", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+// newFixtureCluster returns a cluster with the database, its AppBinding and an
+// event on it. With backup set, Stash is installed and backs the database up.
+// With ops set, the OpsRequest and Autoscaler CRDs are installed and an
+// upgrade has been run on the database.
+func newFixtureCluster(kind string, obj runtime.Object, appType appcat.AppType, port int32, backup, ops bool, objs ...runtime.Object) *fakeCluster {
+	db, err := meta.Accessor(obj)
+	if err != nil {
+		panic(err)
+	}
+	c := &fakeCluster{objects: append([]runtime.Object{obj}, objs...)}
+	c.add(
+		&appcat.AppBinding{
+			ObjectMeta: fixtureMeta(db.GetName(), db.GetLabels()),
+			Spec: appcat.AppBindingSpec{
+				Type: appType,
+				ClientConfig: appcat.ClientConfig{
+					Service: &appcat.ServiceReference{Scheme: "tcp", Name: db.GetName(), Port: port},
+				},
+				Secret: &core.LocalObjectReference{Name: db.GetName() + "-auth"},
+			},
+		},
+		&core.Event{
+			ObjectMeta:     fixtureMeta(db.GetName()+".168467c0f1c06a1c", nil),
+			InvolvedObject: core.ObjectReference{Kind: kind, Namespace: fixtureNamespace, Name: db.GetName()},
+			Type:           core.EventTypeNormal,
+			Reason:         "Successful",
+			Message:        fmt.Sprintf("Successfully created %s", kind),
+			Count:          1,
+			FirstTimestamp: fixtureStarted,
+			LastTimestamp:  fixtureStarted,
+			Source:         core.EventSource{Component: "KubeDB Operator"},
+		},
+	)
+
+	if backup {
+		c.stash = true
+		c.add(
+			&stashV1alpha1.Repository{
+				ObjectMeta: fixtureMeta(db.GetName()+"-repo", nil),
+				Spec: stashV1alpha1.RepositorySpec{
+					Backend: store.Backend{
+						StorageSecretName: "gcs-secret",
+						GCS:               &store.GCSSpec{Bucket: "kubedb-backups", Prefix: "/demo/" + db.GetName()},
+					},
+				},
+			},
+			&stashV1beta1.BackupConfiguration{
+				// custom resources keep their kind in list responses
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: stashV1beta1.SchemeGroupVersion.String(),
+					Kind:       stashV1beta1.ResourceKindBackupConfiguration,
+				},
+				ObjectMeta: fixtureMeta(db.GetName()+"-backup", nil),
+				Spec: stashV1beta1.BackupConfigurationSpec{
+					BackupConfigurationTemplateSpec: stashV1beta1.BackupConfigurationTemplateSpec{
+						Task: stashV1beta1.TaskRef{Name: strings.ToLower(kind) + "-backup"},
+						Target: &stashV1beta1.BackupTarget{
+							Ref: stashV1beta1.TargetRef{
+								APIVersion: appcat.SchemeGroupVersion.String(),
+								Kind:       KindAppBinding,
+								Name:       db.GetName(),
+							},
+						},
+					},
+					Schedule:   "*/30 * * * *",
+					Repository: core.LocalObjectReference{Name: db.GetName() + "-repo"},
+				},
+			},
+			&stashV1beta1.BackupSession{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              db.GetName() + "-backup-1622548800",
+					Namespace:         fixtureNamespace,
+					CreationTimestamp: metav1.NewTime(fixtureNow.Add(-30 * time.Minute)),
+				},
+				Spec: stashV1beta1.BackupSessionSpec{
+					Invoker: stashV1beta1.BackupInvokerRef{
+						APIGroup: stashV1beta1.SchemeGroupVersion.Group,
+						Kind:     stashV1beta1.ResourceKindBackupConfiguration,
+						Name:     db.GetName() + "-backup",
+					},
+				},
+				Status: stashV1beta1.BackupSessionStatus{Phase: stashV1beta1.BackupSessionSucceeded},
+			},
+		)
+	}
+
+	if ops {
+		c.resources = make(map[schema.GroupVersionResource][]unstructured.Unstructured)
+		for opsKind, info := range opsRequestKinds {
+			gvr := opsapi.SchemeGroupVersion.WithResource(info.plural)
+			c.resources[gvr] = nil
+			if info.databaseKind != kind {
+				continue
+			}
+			u := unstructured.Unstructured{}
+			u.SetAPIVersion(opsapi.SchemeGroupVersion.String())
+			u.SetKind(opsKind)
+			u.SetName(db.GetName() + "-upgrade")
+			u.SetNamespace(fixtureNamespace)
+			u.SetCreationTimestamp(metav1.NewTime(fixtureNow.Add(-24 * time.Hour)))
+			_ = unstructured.SetNestedField(u.Object, db.GetName(), "spec", "databaseRef", "name")
+			_ = unstructured.SetNestedField(u.Object, "Upgrade", "spec", "type")
+			_ = unstructured.SetNestedField(u.Object, "Successful", "status", "phase")
+			c.resources[gvr] = append(c.resources[gvr], u)
+		}
+		for _, info := range autoscalerKinds {
+			c.resources[autoscalingapi.SchemeGroupVersion.WithResource(info.plural)] = nil
+		}
+	}
+	return c
+}
+
+func (c *fakeCluster) add(objs ...runtime.Object) {
+	c.objects = append(c.objects, objs...)
+}
+
+func fixtureMeta(name string, labels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         fixtureNamespace,
+		CreationTimestamp: fixtureCreated,
+		Labels:            labels,
+	}
+}
+
+// fixtureMonitor is the monitoring configuration of the fixtures that are
+// monitored.
+func fixtureMonitor() *mona.AgentSpec {
+	return &mona.AgentSpec{
+		Agent: mona.AgentPrometheusOperator,
+		Prometheus: &mona.PrometheusSpec{
+			Exporter: mona.PrometheusExporterSpec{Port: 56790},
+			ServiceMonitor: &mona.ServiceMonitorSpec{
+				Labels:   map[string]string{"release": "prometheus"},
+				Interval: "10s",
+			},
+		},
+	}
+}
+
+// fixtureStorage returns a PVC template requesting the given size.
+func fixtureStorage(size string) *core.PersistentVolumeClaimSpec {
+	storageClass := "standard"
+	return &core.PersistentVolumeClaimSpec{
+		StorageClassName: &storageClass,
+		AccessModes:      []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+		Resources: core.ResourceRequirements{
+			Requests: core.ResourceList{core.ResourceStorage: resource.MustParse(size)},
+		},
+	}
+}
+
+// statefulSet returns a StatefulSet with its running pods. The pods carry the
+// given labels, and the role label when a role is given for them.
+func statefulSet(name string, podLabels map[string]string, replicas int, roles ...string) []runtime.Object {
+	sts := &apps.StatefulSet{
+		ObjectMeta: fixtureMeta(name, podLabels),
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32P(int32(replicas)),
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+		},
+		Status: apps.StatefulSetStatus{Replicas: int32(replicas)},
+	}
+	objs := []runtime.Object{sts}
+	for i := 0; i < replicas; i++ {
+		labels := make(map[string]string, len(podLabels)+1)
+		for k, v := range podLabels {
+			labels[k] = v
+		}
+		if i < len(roles) && roles[i] != "" {
+			labels[api.LabelRole] = roles[i]
+		}
+		pod := &core.Pod{
+			ObjectMeta: fixtureMeta(fmt.Sprintf("%s-%d", name, i), labels),
+			Status: core.PodStatus{
+				Phase:      core.PodRunning,
+				StartTime:  &fixtureStarted,
+				Conditions: []core.PodCondition{{Type: core.PodReady, Status: core.ConditionTrue}},
+				PodIP:      fmt.Sprintf("10.244.0.%d", i+10),
+			},
+		}
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: apps.SchemeGroupVersion.String(),
+			Kind:       "StatefulSet",
+			Name:       name,
+			Controller: &controller,
+		}}
+		objs = append(objs, pod)
+	}
+	return objs
+}
+
+// deployment returns a Deployment with its running pods.
+func deployment(name string, podLabels map[string]string, replicas int) []runtime.Object {
+	r := int32(replicas)
+	d := &apps.Deployment{
+		ObjectMeta: fixtureMeta(name, podLabels),
+		Spec: apps.DeploymentSpec{
+			Replicas: &r,
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+		},
+		Status: apps.DeploymentStatus{Replicas: r, UpdatedReplicas: r, AvailableReplicas: r},
+	}
+	objs := []runtime.Object{d}
+	for i := 0; i < replicas; i++ {
+		objs = append(objs, &core.Pod{
+			ObjectMeta: fixtureMeta(fmt.Sprintf("%s-5d8f7b9c64-%d", name, i), podLabels),
+			Status: core.PodStatus{
+				Phase:      core.PodRunning,
+				StartTime:  &fixtureStarted,
+				Conditions: []core.PodCondition{{Type: core.PodReady, Status: core.ConditionTrue}},
+				PodIP:      fmt.Sprintf("10.244.0.%d", i+10),
+			},
+		})
+	}
+	return objs
+}
+
+// services returns the primary service of a database with its endpoints, and
+// the governing service of its pods.
+func services(name string, selectors map[string]string, portName string, port int32, podIPs ...string) []runtime.Object {
+	primary := &core.Service{
+		ObjectMeta: fixtureMeta(name, selectors),
+		Spec: core.ServiceSpec{
+			Type:      core.ServiceTypeClusterIP,
+			ClusterIP: "10.96.120.14",
+			Selector:  selectors,
+			Ports: []core.ServicePort{{
+				Name:       portName,
+				Port:       port,
+				Protocol:   core.ProtocolTCP,
+				TargetPort: intstr.FromString(portName),
+			}},
+		},
+	}
+	endpoints := &core.Endpoints{
+		ObjectMeta: fixtureMeta(name, selectors),
+		Subsets: []core.EndpointSubset{{
+			Ports: []core.EndpointPort{{Name: portName, Port: port, Protocol: core.ProtocolTCP}},
+		}},
+	}
+	for _, ip := range podIPs {
+		endpoints.Subsets[0].Addresses = append(endpoints.Subsets[0].Addresses, core.EndpointAddress{IP: ip})
+	}
+	governing := &core.Service{
+		ObjectMeta: fixtureMeta(name+"-pods", selectors),
+		Spec: core.ServiceSpec{
+			Type:      core.ServiceTypeClusterIP,
+			ClusterIP: core.ClusterIPNone,
+			Selector:  selectors,
+			Ports: []core.ServicePort{{
+				Name:       portName,
+				Port:       port,
+				Protocol:   core.ProtocolTCP,
+				TargetPort: intstr.FromString(portName),
+			}},
+		},
+	}
+	return []runtime.Object{primary, endpoints, governing}
+}
+
+// podIPs returns the IPs statefulSet gives to the first n pods.
+func podIPs(n int) []string {
+	ips := make([]string, n)
+	for i := range ips {
+		ips[i] = "10.244.0." + strconv.Itoa(i+10)
+	}
+	return ips
+}
+
+// authSecret returns the secret that holds the credentials of a database.
+func authSecret(name string) *core.Secret {
+	return &core.Secret{
+		ObjectMeta: fixtureMeta(name, nil),
+		Type:       core.SecretTypeOpaque,
+		Data: map[string][]byte{
+			core.BasicAuthUsernameKey: []byte("root"),
+			core.BasicAuthPasswordKey: []byte("Xa5dc7vQ2Lh9bN3e"),
+		},
+	}
+}
@@ -0,0 +1,388 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"fmt"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/describe"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	mona "kmodules.xyz/monitoring-agent-api/api/v1"
+)
+
+// The types in this file are the intermediate model built by every describer
+// of this package. The text output of `describe` is rendered from it and
+// `describe -o json|yaml` emits it as is, so the json tags are a public schema.
+// Within DescriptionAPIVersion fields may be added, but never renamed, removed
+// or given a different meaning.
+
+const (
+	DescriptionAPIVersion       = "describe.kubedb.com/v1alpha1"
+	ResourceKindDescription     = "Description"
+	ResourceKindDescriptionList = "DescriptionList"
+)
+
+// ObjectDescriber is implemented by the describers of this package. Besides the
+// text returned by Describe, it gives access to the Description the text is
+// rendered from.
+type ObjectDescriber interface {
+	describe.ResourceDescriber
+	DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error)
+}
+
+// Description describes a single KubeDB object. Every database kind, OpsRequest
+// and Autoscaler uses this schema. The parts that most databases have in common
+// get a field of their own, while the details that only apply to some kinds
+// (e.g. the connection pool of a PgBouncer) are kept in Sections. Fields that do
+// not apply to the described kind are omitted.
+type Description struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Object identifies the described object.
+	Object ObjectInfo `json:"object"`
+	// Fields are the top level properties of the object in display order,
+	// e.g. "Replicas", "Status" and "Termination Policy" for a database.
+	Fields []Field `json:"fields,omitempty"`
+	// Storage is the storage type and the PVC template of a database.
+	Storage *Storage `json:"storage,omitempty"`
+	// Workloads are the StatefulSets and Deployments that run the database.
+	Workloads []Workload `json:"workloads,omitempty"`
+	// Services are the Services that expose the database.
+	Services []Service `json:"services,omitempty"`
+	// Secrets are the Secrets referred to by the object. Only the key names
+	// and the size of the values are included, never the values themselves.
+	Secrets []Secret `json:"secrets,omitempty"`
+	// TLS is the TLS configuration of the database.
+	TLS *kmapi.TLSConfig `json:"tls,omitempty"`
+	// Certificates are the certificates parsed from the secrets of the TLS
+	// configuration.
+	Certificates []Certificate `json:"certificates,omitempty"`
+	// Topology lists the pods of the database with their role in the cluster.
+	Topology *Topology `json:"topology,omitempty"`
+	// Sections hold the information that is specific to the described kind.
+	Sections []Section `json:"sections,omitempty"`
+	// Monitor is the monitoring configuration of the database.
+	Monitor *mona.AgentSpec `json:"monitor,omitempty"`
+	// Init is the initialization configuration of the database.
+	Init *api.InitSpec `json:"init,omitempty"`
+	// OpsRequests are the OpsRequests that target the database, most recent first.
+	OpsRequests []OpsRequestRef `json:"opsRequests,omitempty"`
+	// Autoscalers are the Autoscalers that target the database.
+	Autoscalers []AutoscalerRef `json:"autoscalers,omitempty"`
+	// Backup lists the Stash backup invokers and sessions of the database.
+	// It is omitted when Stash is not installed in the cluster.
+	Backup *Backup `json:"backup,omitempty"`
+	// AppBinding is the AppBinding of the database.
+	AppBinding *AppBinding `json:"appBinding,omitempty"`
+	// Events are the events of the object. It is null when events were not
+	// requested.
+	Events []Event `json:"events"`
+
+	// storageAt is the number of fields that are printed before the storage
+	// in the text output.
+	storageAt int
+}
+
+// DescriptionList is emitted when more than one object is described.
+type DescriptionList struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Items      []*Description `json:"items"`
+}
+
+// ObjectInfo identifies the described object.
+type ObjectInfo struct {
+	APIVersion        string            `json:"apiVersion"`
+	Kind              string            `json:"kind"`
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	CreationTimestamp metav1.Time       `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+}
+
+// Field is a named value. A value that spans several lines is rendered with
+// its lines aligned one below another.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Section is a titled block of information. A section without any content is
+// rendered as <none>.
+type Section struct {
+	Title    string    `json:"title"`
+	Fields   []Field   `json:"fields,omitempty"`
+	Table    *Table    `json:"table,omitempty"`
+	Sections []Section `json:"sections,omitempty"`
+	// Notes are free text lines, e.g. warnings, printed after the rest of
+	// the section.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Table is a list of rows with the same columns.
+type Table struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows,omitempty"`
+}
+
+// Storage is the storage configuration of a database.
+type Storage struct {
+	Type api.StorageType `json:"type"`
+	// Volume is the PVC template, it is nil for databases without volumes.
+	Volume *core.PersistentVolumeClaimSpec `json:"volume,omitempty"`
+}
+
+// Workload is a StatefulSet or a Deployment that runs the database.
+type Workload struct {
+	Kind              string            `json:"kind"`
+	Name              string            `json:"name"`
+	CreationTimestamp metav1.Time       `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Replicas          WorkloadReplicas  `json:"replicas"`
+	Pods              PodCounts         `json:"pods"`
+}
+
+// WorkloadReplicas are the replica counts of a workload. Updated, Available
+// and Unavailable are only reported for Deployments.
+type WorkloadReplicas struct {
+	Desired     int32 `json:"desired"`
+	Total       int32 `json:"total"`
+	Updated     int32 `json:"updated,omitempty"`
+	Available   int32 `json:"available,omitempty"`
+	Unavailable int32 `json:"unavailable,omitempty"`
+}
+
+// PodCounts counts the pods of a workload by phase.
+type PodCounts struct {
+	Running   int `json:"running"`
+	Waiting   int `json:"waiting"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// Service is a Service that exposes the database.
+type Service struct {
+	Name                string            `json:"name"`
+	Labels              map[string]string `json:"labels,omitempty"`
+	Annotations         map[string]string `json:"annotations,omitempty"`
+	Type                core.ServiceType  `json:"type"`
+	ClusterIP           string            `json:"clusterIP,omitempty"`
+	ExternalIPs         []string          `json:"externalIPs,omitempty"`
+	LoadBalancerIP      string            `json:"loadBalancerIP,omitempty"`
+	ExternalName        string            `json:"externalName,omitempty"`
+	LoadBalancerIngress []string          `json:"loadBalancerIngress,omitempty"`
+	Ports               []ServicePort     `json:"ports,omitempty"`
+}
+
+// ServicePort is a port of a Service along with its endpoints.
+type ServicePort struct {
+	Name       string        `json:"name,omitempty"`
+	Port       int32         `json:"port"`
+	Protocol   core.Protocol `json:"protocol"`
+	TargetPort string        `json:"targetPort"`
+	NodePort   int32         `json:"nodePort,omitempty"`
+	// Endpoints are the ready addresses in host:port form.
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// Secret is a Secret used by the object, without its values.
+type Secret struct {
+	// Role tells what the secret is used for, e.g. "Auth" or "Config".
+	Role        string            `json:"role,omitempty"`
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Type        core.SecretType   `json:"type"`
+	Data        []SecretKey       `json:"data,omitempty"`
+}
+
+// SecretKey is a key of a Secret with the size of its value in bytes.
+type SecretKey struct {
+	Key  string `json:"key"`
+	Size int    `json:"size"`
+}
+
+// Certificate is a certificate parsed from the PEM data of a TLS secret. The
+// private key is never read.
+type Certificate struct {
+	Alias      string       `json:"alias"`
+	SecretName string       `json:"secretName"`
+	Subject    string       `json:"subject,omitempty"`
+	SANs       []string     `json:"sans,omitempty"`
+	Issuer     string       `json:"issuer,omitempty"`
+	NotBefore  *metav1.Time `json:"notBefore,omitempty"`
+	NotAfter   *metav1.Time `json:"notAfter,omitempty"`
+	// KeyType is the public key algorithm and size, e.g. "RSA 2048".
+	KeyType string `json:"keyType,omitempty"`
+	// Verified tells whether the chain verifies against the CA in the
+	// same secret.
+	Verified bool `json:"verified"`
+	// Warnings report a missing secret, an expiring certificate or a chain
+	// that does not verify.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Topology lists the pods of a database cluster.
+type Topology struct {
+	// Title names the topology in the text output, e.g. "Topology" or "Galera Cluster".
+	Title   string   `json:"title"`
+	Members []Member `json:"members"`
+}
+
+// Member is a pod of a database cluster.
+type Member struct {
+	Name string `json:"name"`
+	// Roles are the roles of the member, e.g. primary or master|data.
+	Roles []string `json:"roles,omitempty"`
+	// PeerAddress is the address the member is reached at by the other members.
+	PeerAddress string        `json:"peerAddress,omitempty"`
+	StartTime   *metav1.Time  `json:"startTime,omitempty"`
+	Phase       core.PodPhase `json:"phase"`
+	Ready       bool          `json:"ready"`
+}
+
+// OpsRequestRef summarizes an OpsRequest that targets the database.
+type OpsRequestRef struct {
+	Name              string      `json:"name"`
+	Type              string      `json:"type"`
+	Phase             string      `json:"phase"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+}
+
+// AutoscalerRef summarizes an Autoscaler that targets the database.
+type AutoscalerRef struct {
+	Name              string      `json:"name"`
+	Kind              string      `json:"kind"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// Policies are the compute and storage policies of the autoscaler.
+	Policies []Section `json:"policies,omitempty"`
+}
+
+// Backup lists the Stash backup invokers of a database and their sessions.
+type Backup struct {
+	Invokers []BackupInvoker `json:"invokers,omitempty"`
+	Sessions []BackupSession `json:"sessions,omitempty"`
+}
+
+// BackupInvoker is a BackupConfiguration or a BackupBatch that backs up the database.
+type BackupInvoker struct {
+	Name              string      `json:"name"`
+	Kind              string      `json:"kind"`
+	Schedule          string      `json:"schedule"`
+	Task              string      `json:"task"`
+	Repository        string      `json:"repository"`
+	Bucket            string      `json:"bucket"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+}
+
+// BackupSession is a backup run by one of the invokers.
+type BackupSession struct {
+	Name              string      `json:"name"`
+	InvokerKind       string      `json:"invokerKind"`
+	InvokerName       string      `json:"invokerName"`
+	Phase             string      `json:"phase"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+}
+
+// AppBinding is the AppBinding of a database.
+type AppBinding struct {
+	// Found is false when the AppBinding has not been created yet.
+	Found bool `json:"found"`
+	// Object is the AppBinding without the metadata populated by the API server.
+	Object map[string]interface{} `json:"object,omitempty"`
+}
+
+// Event is an event of the described object.
+type Event struct {
+	Type           string      `json:"type"`
+	Reason         string      `json:"reason"`
+	Count          int32       `json:"count"`
+	FirstTimestamp metav1.Time `json:"firstTimestamp"`
+	LastTimestamp  metav1.Time `json:"lastTimestamp"`
+	Source         string      `json:"source"`
+	Message        string      `json:"message"`
+}
+
+// newDescription returns a Description of the object of the given kind with
+// the object's metadata filled in.
+func newDescription(gvk schema.GroupVersionKind, meta metav1.ObjectMeta) *Description {
+	return &Description{
+		APIVersion: DescriptionAPIVersion,
+		Kind:       ResourceKindDescription,
+		Object: ObjectInfo{
+			APIVersion:        gvk.GroupVersion().String(),
+			Kind:              gvk.Kind,
+			Name:              meta.Name,
+			Namespace:         meta.Namespace,
+			CreationTimestamp: meta.CreationTimestamp,
+			Labels:            meta.Labels,
+			Annotations:       meta.Annotations,
+		},
+	}
+}
+
+func (d *Description) addField(name, format string, args ...interface{}) {
+	d.Fields = append(d.Fields, Field{Name: name, Value: fmt.Sprintf(format, args...)})
+}
+
+// setStorage sets the storage of a database. The storage is printed after the
+// fields added so far in the text output.
+func (d *Description) setStorage(st api.StorageType, volume *core.PersistentVolumeClaimSpec) {
+	d.Storage = &Storage{Type: st, Volume: volume}
+	d.storageAt = len(d.Fields)
+}
+
+func (d *Description) addSection(s *Section) {
+	if s != nil {
+		d.Sections = append(d.Sections, *s)
+	}
+}
+
+func (s *Section) addField(name, format string, args ...interface{}) {
+	s.Fields = append(s.Fields, Field{Name: name, Value: fmt.Sprintf(format, args...)})
+}
+
+func (s *Section) addNote(format string, args ...interface{}) {
+	s.Notes = append(s.Notes, fmt.Sprintf(format, args...))
+}
+
+func (s *Section) addSection(sub *Section) {
+	if sub != nil {
+		s.Sections = append(s.Sections, *sub)
+	}
+}
+
+func newTable(columns ...string) *Table {
+	return &Table{Columns: columns}
+}
+
+// addRow appends a row to the table, formatting each cell with fmt.Sprint.
+func (t *Table) addRow(cells ...interface{}) {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = fmt.Sprint(c)
+	}
+	t.Rows = append(t.Rows, row)
+}
@@ -18,10 +18,10 @@ package describer
 
 import (
 	"context"
-	"io"
+	"sort"
+	"strings"
 
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
-	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
 
 	"gomodules.xyz/pointer"
@@ -29,6 +29,7 @@ import (
 	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/describe"
 	kmapi "kmodules.xyz/client-go/api/v1"
@@ -39,98 +40,212 @@ import (
 )
 
 type ElasticsearchDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha2Interface
-	stash  stash.Interface
-	appcat appcat_cs.Interface
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
 }
 
 func (d *ElasticsearchDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.Elasticsearches(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
 	if err != nil {
 		return "", err
 	}
+	return RenderText(desc)
+}
+
+func (d *ElasticsearchDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.Elasticsearches(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
 
-	var events *core.EventList
+	var events []Event
 	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
+		events, err = getEvents(d.client, item.Namespace, item)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
 	return d.describeElasticsearch(item, selector, events)
 }
 
-func (d *ElasticsearchDescriber) describeElasticsearch(item *api.Elasticsearch, selector labels.Selector, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := describe.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", pointer.Int32(item.Spec.Replicas))
-		}
+func (d *ElasticsearchDescriber) describeElasticsearch(item *api.Elasticsearch, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindElasticsearch), item.ObjectMeta)
+	desc.addField("Status", "%s", item.Status.Phase)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	if item.Spec.Topology == nil {
+		desc.setStorage(item.Spec.StorageType, item.Spec.Storage)
+	}
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+	desc.addField("Halted", "%v", item.Spec.Halted)
+	desc.addField("Termination Policy", "%v", item.Spec.TerminationPolicy)
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
 
-		w.Write(LEVEL_0, "Paused:\t%v\n", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
-		w.Write(LEVEL_0, "Halted:\t%v\n", item.Spec.Halted)
-		w.Write(LEVEL_0, "Termination Policy:\t%v\n", item.Spec.TerminationPolicy)
+	secrets := make(map[string]*core.LocalObjectReference)
+	if item.Spec.AuthSecret != nil {
+		secrets["Auth"] = item.Spec.AuthSecret
+	}
+	desc.Secrets = getSecrets(d.client, item.Namespace, secrets)
 
-		showWorkload(d.client, item.Namespace, selector, w)
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
 
-		secrets := make(map[string]*core.LocalObjectReference)
-		if item.Spec.AuthSecret != nil {
-			secrets["Auth"] = item.Spec.AuthSecret
-		}
-		showSecret(d.client, item.Namespace, secrets, w)
+	specific := make(map[string]labels.Selector)
+	for _, role := range elasticsearchNodeRoles {
+		specific[string(role)] = labels.SelectorFromSet(map[string]string{
+			item.NodeRoleSpecificLabelKey(role): api.ElasticsearchNodeRoleSet,
+		})
+	}
+	desc.Topology = getTopology(d.client, item.Namespace, selector, specific)
 
-		specific := map[string]labels.Selector{
-			"master": labels.SelectorFromSet(map[string]string{"node.role.master": "set"}),
-			"client": labels.SelectorFromSet(map[string]string{"node.role.client": "set"}),
-			"data":   labels.SelectorFromSet(map[string]string{"node.role.data": "set"}),
-		}
-		showTopology(d.client, item.Namespace, selector, specific, w)
+	if item.Spec.Topology != nil {
+		desc.addSection(getElasticsearchNodeTopology(item))
+	}
+	if len(item.Spec.InternalUsers) > 0 {
+		desc.addSection(getElasticsearchInternalUsers(item.Spec.InternalUsers))
+	}
+	if item.Spec.KernelSettings != nil {
+		desc.addSection(getKernelSettings(item.Spec.KernelSettings))
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+	desc.Monitor = item.Spec.Monitor
+	desc.Init = item.Spec.Init
 
-		// Show Initialization information
-		describeInitialization(item.Spec.Init, w)
+	var err error
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindElasticsearch, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindElasticsearch, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
 
-		ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
-		if err != nil && !kerr.IsNotFound(err) {
-			return err
+	ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return nil, err
+	}
+
+	// Backup information
+	if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		desc.Backup, err = getBackup(d.stash, ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Show Backup information
-		if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
-			err = showBackups(d.stash, ab, w)
-			if err != nil {
-				return err
-			}
+	if ab != nil {
+		desc.AppBinding, err = getAppBinding(ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Show AppBinding
-		if ab != nil {
-			err = showAppBinding(ab, w)
-			if err != nil {
-				return err
-			}
+	desc.Events = events
+	return desc, nil
+}
+
+var elasticsearchNodeRoles = []api.ElasticsearchNodeRoleType{
+	api.ElasticsearchNodeRoleTypeMaster,
+	api.ElasticsearchNodeRoleTypeIngest,
+	api.ElasticsearchNodeRoleTypeData,
+	api.ElasticsearchNodeRoleTypeDataContent,
+	api.ElasticsearchNodeRoleTypeDataHot,
+	api.ElasticsearchNodeRoleTypeDataWarm,
+	api.ElasticsearchNodeRoleTypeDataCold,
+	api.ElasticsearchNodeRoleTypeDataFrozen,
+	api.ElasticsearchNodeRoleTypeML,
+	api.ElasticsearchNodeRoleTypeTransform,
+	api.ElasticsearchNodeRoleTypeCoordinating,
+}
+
+// getElasticsearchNodeTopology returns one section per node tier of an
+// Elasticsearch that runs dedicated nodes. Tiers that are not configured are
+// left out.
+func getElasticsearchNodeTopology(item *api.Elasticsearch) *Section {
+	t := item.Spec.Topology
+	section := &Section{Title: "Node Topology"}
+
+	addTier := func(title string, node *api.ElasticsearchNode, stsName func() string) {
+		if node == nil {
+			return
+		}
+		tier := &Section{Title: title}
+		tier.addField("StatefulSet", "%s", stsName())
+		if node.Suffix != "" {
+			tier.addField("Suffix", "%s", node.Suffix)
+		} else {
+			tier.addField("Suffix", "%s", ValueNone)
 		}
+		if node.Replicas != nil {
+			tier.addField("Replicas", "%d  total", pointer.Int32(node.Replicas))
+		}
+		tier.addField("Requests", "%s", formatResourceList(node.Resources.Requests))
+		tier.addField("Limits", "%s", formatResourceList(node.Resources.Limits))
+		if node.MaxUnavailable != nil {
+			tier.addField("Max Unavailable", "%s", node.MaxUnavailable.String())
+		}
+		tier.addSection(getStorageSection(item.Spec.StorageType, node.Storage))
+		section.addSection(tier)
+	}
+
+	addTier("Master", &t.Master, item.MasterStatefulSetName)
+	addTier("Ingest", &t.Ingest, item.IngestStatefulSetName)
+	addTier("Data", t.Data, item.DataStatefulSetName)
+	addTier("Data Content", t.DataContent, item.DataContentStatefulSetName)
+	addTier("Data Hot", t.DataHot, item.DataHotStatefulSetName)
+	addTier("Data Warm", t.DataWarm, item.DataWarmStatefulSetName)
+	addTier("Data Cold", t.DataCold, item.DataColdStatefulSetName)
+	addTier("Data Frozen", t.DataFrozen, item.DataFrozenStatefulSetName)
+	addTier("ML", t.ML, item.MLStatefulSetName)
+	addTier("Transform", t.Transform, item.TransformStatefulSetName)
+	addTier("Coordinating", t.Coordinating, item.CoordinatingStatefulSetName)
+	return section
+}
 
-		if events != nil {
-			DescribeEvents(events, w)
+// getElasticsearchInternalUsers lists the internal users sorted by name. The
+// password hashes are never shown, only the secrets that hold the credentials.
+func getElasticsearchInternalUsers(users map[string]api.ElasticsearchUserSpec) *Section {
+	names := make([]string, 0, len(users))
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	section := &Section{Title: "Internal Users"}
+	section.Table = newTable("User", "Reserved", "Hidden", "Secret", "Backend Roles")
+	for _, name := range names {
+		user := users[name]
+		secretName := user.SecretName
+		if secretName == "" {
+			secretName = ValueNone
+		}
+		backendRoles := ValueNone
+		if len(user.BackendRoles) > 0 {
+			backendRoles = strings.Join(user.BackendRoles, ",")
 		}
+		section.Table.addRow(name, user.Reserved, user.Hidden, secretName, backendRoles)
+	}
+	return section
+}
 
-		return nil
-	})
+// getKernelSettings returns the sysctls that the init container applies to
+// the nodes the database runs on.
+func getKernelSettings(ks *api.KernelSettings) *Section {
+	section := &Section{Title: "Kernel Settings"}
+	section.addField("Privileged", "%v", ks.Privileged)
+	section.Table = newTable("Sysctl", "Value")
+	for _, sysctl := range ks.Sysctls {
+		section.Table.addRow(sysctl.Name, sysctl.Value)
+	}
+	return section
 }
@@ -0,0 +1,680 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	appsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/kubectl/pkg/describe"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+	appcat_cs "kmodules.xyz/custom-resources/client/clientset/versioned"
+	appcat_v1alpha1 "kmodules.xyz/custom-resources/client/clientset/versioned/typed/appcatalog/v1alpha1"
+	stashV1alpha1 "stash.appscode.dev/apimachinery/apis/stash/v1alpha1"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+	stash "stash.appscode.dev/apimachinery/client/clientset/versioned"
+	stash_v1alpha1 "stash.appscode.dev/apimachinery/client/clientset/versioned/typed/stash/v1alpha1"
+	stash_v1beta1 "stash.appscode.dev/apimachinery/client/clientset/versioned/typed/stash/v1beta1"
+)
+
+// The fakes in this file serve the describers from an in-memory list of
+// objects. The generated fake clientsets are not vendored, so each fake embeds
+// the interface it implements and only overrides the calls the describers
+// make. Any other call panics on the nil embedded interface, which makes an
+// unexpected API call fail the test loudly.
+
+// fakeCluster holds the objects served by the fake clients.
+type fakeCluster struct {
+	objects []runtime.Object
+	// stash tells whether the Stash CRDs are installed.
+	stash bool
+	// resources are served through the dynamic client. Listing a resource
+	// that is not in the map fails with NotFound, like a missing CRD does.
+	resources map[schema.GroupVersionResource][]unstructured.Unstructured
+
+	mu sync.Mutex
+	// lists counts the List calls made for each type of object.
+	lists map[reflect.Type]int
+}
+
+// newDescribers returns the describers of this package backed by the cluster,
+// sharing a client cache like the ones built by describerMap.
+func (c *fakeCluster) newDescribers() map[schema.GroupKind]describe.ResourceDescriber {
+	cache := newClientCache(fakeKube{c: c}, fakeStash{c: c}, fakeDynamic{c: c})
+	return newDescriberMap(cache.kubeClient(), fakeKubeDB{c: c}, cache.stashClient(), fakeAppcat{c: c}, cache.dynamicClient(), DefaultOptions())
+}
+
+// listCalls returns the number of List calls made for objects of the same
+// type as proto.
+func (c *fakeCluster) listCalls(proto runtime.Object) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lists[reflect.TypeOf(proto)]
+}
+
+// list returns copies of the objects of the same type as proto in the
+// namespace that match the label selector of opts.
+func (c *fakeCluster) list(proto runtime.Object, namespace string, opts metav1.ListOptions) ([]runtime.Object, error) {
+	c.mu.Lock()
+	if c.lists == nil {
+		c.lists = map[reflect.Type]int{}
+	}
+	c.lists[reflect.TypeOf(proto)]++
+	c.mu.Unlock()
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	var result []runtime.Object
+	for _, obj := range c.objects {
+		if reflect.TypeOf(obj) != reflect.TypeOf(proto) {
+			continue
+		}
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		if namespace != "" && m.GetNamespace() != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(m.GetLabels())) {
+			continue
+		}
+		result = append(result, obj.DeepCopyObject())
+	}
+	return result, nil
+}
+
+// get returns a copy of the named object of the same type as proto. Like the
+// generated clients, it returns proto along with the error when the object is
+// not found.
+func (c *fakeCluster) get(proto runtime.Object, namespace, name string) (runtime.Object, error) {
+	for _, obj := range c.objects {
+		if reflect.TypeOf(obj) != reflect.TypeOf(proto) {
+			continue
+		}
+		if m, _ := meta.Accessor(obj); m.GetNamespace() == namespace && m.GetName() == name {
+			return obj.DeepCopyObject(), nil
+		}
+	}
+	resource := strings.ToLower(reflect.TypeOf(proto).Elem().Name()) + "s"
+	return proto, kerr.NewNotFound(schema.GroupResource{Resource: resource}, name)
+}
+
+type fakeKube struct {
+	kubernetes.Interface
+	c *fakeCluster
+}
+
+func (f fakeKube) CoreV1() corev1.CoreV1Interface { return fakeCoreV1{c: f.c} }
+
+func (f fakeKube) AppsV1() appsv1.AppsV1Interface { return fakeAppsV1{c: f.c} }
+
+func (f fakeKube) Discovery() discovery.DiscoveryInterface { return fakeDiscovery{c: f.c} }
+
+type fakeDiscovery struct {
+	discovery.DiscoveryInterface
+	c *fakeCluster
+}
+
+// ServerGroups lists the core group, and the stash.appscode.com group if the
+// Stash CRDs are installed. The describers read discovery through a memory
+// cache, which only calls ServerGroups and ServerResourcesForGroupVersion.
+func (f fakeDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	coreGV := metav1.GroupVersionForDiscovery{GroupVersion: "v1", Version: "v1"}
+	list := &metav1.APIGroupList{
+		Groups: []metav1.APIGroup{
+			{Versions: []metav1.GroupVersionForDiscovery{coreGV}, PreferredVersion: coreGV},
+		},
+	}
+	if f.c.stash {
+		gv := metav1.GroupVersionForDiscovery{
+			GroupVersion: stashV1beta1.SchemeGroupVersion.String(),
+			Version:      stashV1beta1.SchemeGroupVersion.Version,
+		}
+		list.Groups = append(list.Groups, metav1.APIGroup{
+			Name:             stashV1beta1.SchemeGroupVersion.Group,
+			Versions:         []metav1.GroupVersionForDiscovery{gv},
+			PreferredVersion: gv,
+		})
+	}
+	return list, nil
+}
+
+func (f fakeDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	if groupVersion == "v1" {
+		return &metav1.APIResourceList{GroupVersion: groupVersion}, nil
+	}
+	if !f.c.stash || groupVersion != stashV1beta1.SchemeGroupVersion.String() {
+		return nil, kerr.NewNotFound(schema.GroupResource{}, groupVersion)
+	}
+	return &metav1.APIResourceList{
+		GroupVersion: groupVersion,
+		APIResources: []metav1.APIResource{
+			{Name: stashV1beta1.ResourcePluralBackupBlueprint, Kind: stashV1beta1.ResourceKindBackupBlueprint},
+		},
+	}, nil
+}
+
+type fakeCoreV1 struct {
+	corev1.CoreV1Interface
+	c *fakeCluster
+}
+
+func (f fakeCoreV1) Pods(namespace string) corev1.PodInterface {
+	return fakePods{c: f.c, ns: namespace}
+}
+
+func (f fakeCoreV1) Services(namespace string) corev1.ServiceInterface {
+	return fakeServices{c: f.c, ns: namespace}
+}
+
+func (f fakeCoreV1) Endpoints(namespace string) corev1.EndpointsInterface {
+	return fakeEndpoints{c: f.c, ns: namespace}
+}
+
+func (f fakeCoreV1) Secrets(namespace string) corev1.SecretInterface {
+	return fakeSecrets{c: f.c, ns: namespace}
+}
+
+func (f fakeCoreV1) Events(namespace string) corev1.EventInterface {
+	return fakeEvents{c: f.c, ns: namespace}
+}
+
+type fakePods struct {
+	corev1.PodInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakePods) List(_ context.Context, opts metav1.ListOptions) (*core.PodList, error) {
+	objs, err := f.c.list(&core.Pod{}, f.ns, opts)
+	list := &core.PodList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*core.Pod))
+	}
+	return list, err
+}
+
+type fakeServices struct {
+	corev1.ServiceInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeServices) List(_ context.Context, opts metav1.ListOptions) (*core.ServiceList, error) {
+	objs, err := f.c.list(&core.Service{}, f.ns, opts)
+	list := &core.ServiceList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*core.Service))
+	}
+	return list, err
+}
+
+type fakeEndpoints struct {
+	corev1.EndpointsInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeEndpoints) List(_ context.Context, opts metav1.ListOptions) (*core.EndpointsList, error) {
+	objs, err := f.c.list(&core.Endpoints{}, f.ns, opts)
+	list := &core.EndpointsList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*core.Endpoints))
+	}
+	return list, err
+}
+
+func (f fakeEndpoints) Get(_ context.Context, name string, _ metav1.GetOptions) (*core.Endpoints, error) {
+	obj, err := f.c.get(&core.Endpoints{}, f.ns, name)
+	return obj.(*core.Endpoints), err
+}
+
+type fakeSecrets struct {
+	corev1.SecretInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeSecrets) Get(_ context.Context, name string, _ metav1.GetOptions) (*core.Secret, error) {
+	obj, err := f.c.get(&core.Secret{}, f.ns, name)
+	return obj.(*core.Secret), err
+}
+
+type fakeEvents struct {
+	corev1.EventInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeEvents) List(_ context.Context, opts metav1.ListOptions) (*core.EventList, error) {
+	objs, err := f.c.list(&core.Event{}, f.ns, opts)
+	list := &core.EventList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*core.Event))
+	}
+	return list, err
+}
+
+// Search returns the events whose involved object has the name of objOrRef.
+func (f fakeEvents) Search(_ *runtime.Scheme, objOrRef runtime.Object) (*core.EventList, error) {
+	m, err := meta.Accessor(objOrRef)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := f.c.list(&core.Event{}, f.ns, metav1.ListOptions{})
+	list := &core.EventList{}
+	for _, obj := range objs {
+		if e := obj.(*core.Event); e.InvolvedObject.Name == m.GetName() {
+			list.Items = append(list.Items, *e)
+		}
+	}
+	return list, err
+}
+
+type fakeAppsV1 struct {
+	appsv1.AppsV1Interface
+	c *fakeCluster
+}
+
+func (f fakeAppsV1) StatefulSets(namespace string) appsv1.StatefulSetInterface {
+	return fakeStatefulSets{c: f.c, ns: namespace}
+}
+
+func (f fakeAppsV1) Deployments(namespace string) appsv1.DeploymentInterface {
+	return fakeDeployments{c: f.c, ns: namespace}
+}
+
+type fakeStatefulSets struct {
+	appsv1.StatefulSetInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeStatefulSets) List(_ context.Context, opts metav1.ListOptions) (*apps.StatefulSetList, error) {
+	objs, err := f.c.list(&apps.StatefulSet{}, f.ns, opts)
+	list := &apps.StatefulSetList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*apps.StatefulSet))
+	}
+	return list, err
+}
+
+type fakeDeployments struct {
+	appsv1.DeploymentInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeDeployments) List(_ context.Context, opts metav1.ListOptions) (*apps.DeploymentList, error) {
+	objs, err := f.c.list(&apps.Deployment{}, f.ns, opts)
+	list := &apps.DeploymentList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*apps.Deployment))
+	}
+	return list, err
+}
+
+type fakeKubeDB struct {
+	cs.KubedbV1alpha2Interface
+	c *fakeCluster
+}
+
+func (f fakeKubeDB) Elasticsearches(namespace string) cs.ElasticsearchInterface {
+	return fakeElasticsearches{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) Etcds(namespace string) cs.EtcdInterface {
+	return fakeEtcds{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) MariaDBs(namespace string) cs.MariaDBInterface {
+	return fakeMariaDBs{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) Memcacheds(namespace string) cs.MemcachedInterface {
+	return fakeMemcacheds{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) MongoDBs(namespace string) cs.MongoDBInterface {
+	return fakeMongoDBs{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) MySQLs(namespace string) cs.MySQLInterface {
+	return fakeMySQLs{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) PerconaXtraDBs(namespace string) cs.PerconaXtraDBInterface {
+	return fakePerconaXtraDBs{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) PgBouncers(namespace string) cs.PgBouncerInterface {
+	return fakePgBouncers{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) Postgreses(namespace string) cs.PostgresInterface {
+	return fakePostgreses{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) ProxySQLs(namespace string) cs.ProxySQLInterface {
+	return fakeProxySQLs{c: f.c, ns: namespace}
+}
+
+func (f fakeKubeDB) Redises(namespace string) cs.RedisInterface {
+	return fakeRedises{c: f.c, ns: namespace}
+}
+
+type fakeElasticsearches struct {
+	cs.ElasticsearchInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeElasticsearches) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.Elasticsearch, error) {
+	obj, err := f.c.get(&api.Elasticsearch{}, f.ns, name)
+	return obj.(*api.Elasticsearch), err
+}
+
+type fakeEtcds struct {
+	cs.EtcdInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeEtcds) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.Etcd, error) {
+	obj, err := f.c.get(&api.Etcd{}, f.ns, name)
+	return obj.(*api.Etcd), err
+}
+
+type fakeMariaDBs struct {
+	cs.MariaDBInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeMariaDBs) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.MariaDB, error) {
+	obj, err := f.c.get(&api.MariaDB{}, f.ns, name)
+	return obj.(*api.MariaDB), err
+}
+
+type fakeMemcacheds struct {
+	cs.MemcachedInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeMemcacheds) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.Memcached, error) {
+	obj, err := f.c.get(&api.Memcached{}, f.ns, name)
+	return obj.(*api.Memcached), err
+}
+
+type fakeMongoDBs struct {
+	cs.MongoDBInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeMongoDBs) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.MongoDB, error) {
+	obj, err := f.c.get(&api.MongoDB{}, f.ns, name)
+	return obj.(*api.MongoDB), err
+}
+
+type fakeMySQLs struct {
+	cs.MySQLInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeMySQLs) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.MySQL, error) {
+	obj, err := f.c.get(&api.MySQL{}, f.ns, name)
+	return obj.(*api.MySQL), err
+}
+
+type fakePerconaXtraDBs struct {
+	cs.PerconaXtraDBInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakePerconaXtraDBs) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.PerconaXtraDB, error) {
+	obj, err := f.c.get(&api.PerconaXtraDB{}, f.ns, name)
+	return obj.(*api.PerconaXtraDB), err
+}
+
+type fakePgBouncers struct {
+	cs.PgBouncerInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakePgBouncers) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.PgBouncer, error) {
+	obj, err := f.c.get(&api.PgBouncer{}, f.ns, name)
+	return obj.(*api.PgBouncer), err
+}
+
+type fakePostgreses struct {
+	cs.PostgresInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakePostgreses) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.Postgres, error) {
+	obj, err := f.c.get(&api.Postgres{}, f.ns, name)
+	return obj.(*api.Postgres), err
+}
+
+type fakeProxySQLs struct {
+	cs.ProxySQLInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeProxySQLs) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.ProxySQL, error) {
+	obj, err := f.c.get(&api.ProxySQL{}, f.ns, name)
+	return obj.(*api.ProxySQL), err
+}
+
+type fakeRedises struct {
+	cs.RedisInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeRedises) Get(_ context.Context, name string, _ metav1.GetOptions) (*api.Redis, error) {
+	obj, err := f.c.get(&api.Redis{}, f.ns, name)
+	return obj.(*api.Redis), err
+}
+
+type fakeAppcat struct {
+	appcat_cs.Interface
+	c *fakeCluster
+}
+
+func (f fakeAppcat) AppcatalogV1alpha1() appcat_v1alpha1.AppcatalogV1alpha1Interface {
+	return fakeAppcatalogV1alpha1{c: f.c}
+}
+
+type fakeAppcatalogV1alpha1 struct {
+	appcat_v1alpha1.AppcatalogV1alpha1Interface
+	c *fakeCluster
+}
+
+func (f fakeAppcatalogV1alpha1) AppBindings(namespace string) appcat_v1alpha1.AppBindingInterface {
+	return fakeAppBindings{c: f.c, ns: namespace}
+}
+
+type fakeAppBindings struct {
+	appcat_v1alpha1.AppBindingInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeAppBindings) Get(_ context.Context, name string, _ metav1.GetOptions) (*appcat.AppBinding, error) {
+	obj, err := f.c.get(&appcat.AppBinding{}, f.ns, name)
+	return obj.(*appcat.AppBinding), err
+}
+
+type fakeStash struct {
+	stash.Interface
+	c *fakeCluster
+}
+
+func (f fakeStash) StashV1alpha1() stash_v1alpha1.StashV1alpha1Interface {
+	return fakeStashV1alpha1{c: f.c}
+}
+
+func (f fakeStash) StashV1beta1() stash_v1beta1.StashV1beta1Interface {
+	return fakeStashV1beta1{c: f.c}
+}
+
+type fakeStashV1alpha1 struct {
+	stash_v1alpha1.StashV1alpha1Interface
+	c *fakeCluster
+}
+
+func (f fakeStashV1alpha1) Repositories(namespace string) stash_v1alpha1.RepositoryInterface {
+	return fakeRepositories{c: f.c, ns: namespace}
+}
+
+type fakeRepositories struct {
+	stash_v1alpha1.RepositoryInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeRepositories) Get(_ context.Context, name string, _ metav1.GetOptions) (*stashV1alpha1.Repository, error) {
+	obj, err := f.c.get(&stashV1alpha1.Repository{}, f.ns, name)
+	return obj.(*stashV1alpha1.Repository), err
+}
+
+type fakeStashV1beta1 struct {
+	stash_v1beta1.StashV1beta1Interface
+	c *fakeCluster
+}
+
+func (f fakeStashV1beta1) BackupConfigurations(namespace string) stash_v1beta1.BackupConfigurationInterface {
+	return fakeBackupConfigurations{c: f.c, ns: namespace}
+}
+
+func (f fakeStashV1beta1) BackupBatches(namespace string) stash_v1beta1.BackupBatchInterface {
+	return fakeBackupBatches{c: f.c, ns: namespace}
+}
+
+func (f fakeStashV1beta1) BackupSessions(namespace string) stash_v1beta1.BackupSessionInterface {
+	return fakeBackupSessions{c: f.c, ns: namespace}
+}
+
+type fakeBackupConfigurations struct {
+	stash_v1beta1.BackupConfigurationInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeBackupConfigurations) List(_ context.Context, opts metav1.ListOptions) (*stashV1beta1.BackupConfigurationList, error) {
+	objs, err := f.c.list(&stashV1beta1.BackupConfiguration{}, f.ns, opts)
+	list := &stashV1beta1.BackupConfigurationList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*stashV1beta1.BackupConfiguration))
+	}
+	return list, err
+}
+
+type fakeBackupBatches struct {
+	stash_v1beta1.BackupBatchInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeBackupBatches) List(_ context.Context, opts metav1.ListOptions) (*stashV1beta1.BackupBatchList, error) {
+	objs, err := f.c.list(&stashV1beta1.BackupBatch{}, f.ns, opts)
+	list := &stashV1beta1.BackupBatchList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*stashV1beta1.BackupBatch))
+	}
+	return list, err
+}
+
+type fakeBackupSessions struct {
+	stash_v1beta1.BackupSessionInterface
+	c  *fakeCluster
+	ns string
+}
+
+func (f fakeBackupSessions) List(_ context.Context, opts metav1.ListOptions) (*stashV1beta1.BackupSessionList, error) {
+	objs, err := f.c.list(&stashV1beta1.BackupSession{}, f.ns, opts)
+	list := &stashV1beta1.BackupSessionList{}
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj.(*stashV1beta1.BackupSession))
+	}
+	return list, err
+}
+
+type fakeDynamic struct {
+	dynamic.Interface
+	c *fakeCluster
+}
+
+func (f fakeDynamic) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return fakeResource{c: f.c, gvr: gvr}
+}
+
+type fakeResource struct {
+	dynamic.NamespaceableResourceInterface
+	c   *fakeCluster
+	gvr schema.GroupVersionResource
+	ns  string
+}
+
+func (f fakeResource) Namespace(namespace string) dynamic.ResourceInterface {
+	f.ns = namespace
+	return f
+}
+
+func (f fakeResource) List(_ context.Context, _ metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	items, ok := f.c.resources[f.gvr]
+	if !ok {
+		return nil, kerr.NewNotFound(f.gvr.GroupResource(), "")
+	}
+	list := &unstructured.UnstructuredList{}
+	for _, u := range items {
+		if f.ns == "" || u.GetNamespace() == f.ns {
+			list.Items = append(list.Items, *u.DeepCopy())
+		}
+	}
+	return list, nil
+}
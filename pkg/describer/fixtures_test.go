@@ -0,0 +1,554 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"strconv"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+
+	"gomodules.xyz/pointer"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+)
+
+// fixtures returns a standalone and a highly available setup of every
+// database kind. The standalone ones are neither monitored nor backed up and
+// run in a cluster without the Stash and OpsRequest CRDs. The highly available
+// ones are monitored, backed up by Stash and have been upgraded once.
+func fixtures() []fixture {
+	var result []fixture
+	for _, fn := range []func(ha bool) fixture{
+		elasticsearchFixture,
+		etcdFixture,
+		mariadbFixture,
+		memcachedFixture,
+		mongodbFixture,
+		mysqlFixture,
+		perconaXtraDBFixture,
+		pgbouncerFixture,
+		postgresFixture,
+		proxysqlFixture,
+		redisFixture,
+	} {
+		result = append(result, fn(false), fn(true))
+	}
+	return result
+}
+
+func fixtureName(kind string, ha bool) string {
+	if ha {
+		return kind + "-ha"
+	}
+	return kind + "-standalone"
+}
+
+func elasticsearchFixture(ha bool) fixture {
+	db := &api.Elasticsearch{
+		ObjectMeta: fixtureMeta("es", nil),
+		Spec: api.ElasticsearchSpec{
+			Version:           "7.9.1-xpack",
+			Replicas:          pointer.Int32P(1),
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			AuthSecret:        &core.LocalObjectReference{Name: "es-auth"},
+			TerminationPolicy: api.TerminationPolicyDelete,
+		},
+		Status: api.ElasticsearchStatus{Phase: api.DatabasePhaseReady},
+	}
+	selectors := db.OffshootSelectors()
+	roleLabels := func(roles ...api.ElasticsearchNodeRoleType) map[string]string {
+		labels := db.OffshootSelectors()
+		for _, role := range roles {
+			labels[db.NodeRoleSpecificLabelKey(role)] = api.ElasticsearchNodeRoleSet
+		}
+		return labels
+	}
+
+	var objs []runtime.Object
+	if !ha {
+		objs = append(objs, statefulSet(db.OffshootName(), roleLabels(
+			api.ElasticsearchNodeRoleTypeMaster,
+			api.ElasticsearchNodeRoleTypeIngest,
+			api.ElasticsearchNodeRoleTypeData,
+		), 1)...)
+		objs = append(objs, services(db.ServiceName(), selectors, "http", 9200, podIPs(1)...)...)
+	} else {
+		db.Spec.Replicas = nil
+		db.Spec.Storage = nil
+		db.Spec.Topology = &api.ElasticsearchClusterTopology{
+			Master: api.ElasticsearchNode{
+				Replicas: pointer.Int32P(3),
+				Suffix:   "master",
+				Storage:  fixtureStorage("1Gi"),
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{core.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+			Ingest: api.ElasticsearchNode{
+				Replicas: pointer.Int32P(2),
+				Suffix:   "ingest",
+				Storage:  fixtureStorage("1Gi"),
+			},
+			Data: &api.ElasticsearchNode{
+				Replicas: pointer.Int32P(2),
+				Suffix:   "data",
+				Storage:  fixtureStorage("10Gi"),
+			},
+		}
+		db.Spec.InternalUsers = map[string]api.ElasticsearchUserSpec{
+			"admin":            {Reserved: true, SecretName: "es-admin-cred"},
+			"kibanaserver":     {Reserved: true, SecretName: "es-kibanaserver-cred"},
+			"metrics_exporter": {BackendRoles: []string{"readall_and_monitor"}},
+		}
+		db.Spec.KernelSettings = &api.KernelSettings{
+			Privileged: true,
+			Sysctls:    []core.Sysctl{{Name: "vm.max_map_count", Value: "262144"}},
+		}
+		db.Spec.Monitor = fixtureMonitor()
+		objs = append(objs, statefulSet(db.MasterStatefulSetName(), roleLabels(api.ElasticsearchNodeRoleTypeMaster), 3)...)
+		objs = append(objs, statefulSet(db.IngestStatefulSetName(), roleLabels(api.ElasticsearchNodeRoleTypeIngest), 2)...)
+		objs = append(objs, statefulSet(db.DataStatefulSetName(), roleLabels(api.ElasticsearchNodeRoleTypeData), 2)...)
+		objs = append(objs, services(db.ServiceName(), selectors, "http", 9200, podIPs(2)...)...)
+	}
+	objs = append(objs, authSecret("es-auth"))
+
+	return fixture{
+		name:    fixtureName("elasticsearch", ha),
+		kind:    api.ResourceKindElasticsearch,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindElasticsearch, db, db.AppBindingMeta().Type(), 9200, ha, ha, objs...),
+	}
+}
+
+func etcdFixture(ha bool) fixture {
+	replicas := 1
+	if ha {
+		replicas = 3
+	}
+	db := &api.Etcd{
+		ObjectMeta: fixtureMeta("etcd", nil),
+		Spec: api.EtcdSpec{
+			Version:           "3.4.3",
+			Replicas:          pointer.Int32P(int32(replicas)),
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			AuthSecret:        &core.LocalObjectReference{Name: "etcd-auth"},
+			TerminationPolicy: api.TerminationPolicyHalt,
+		},
+		Status: api.EtcdStatus{Phase: api.DatabasePhaseReady},
+	}
+	if ha {
+		db.Spec.Monitor = fixtureMonitor()
+	}
+	selectors := db.OffshootSelectors()
+
+	objs := statefulSet(db.OffshootName(), selectors, replicas)
+	objs = append(objs, services(db.OffshootName(), selectors, "client", 2379, podIPs(replicas)...)...)
+	objs = append(objs, authSecret("etcd-auth"))
+
+	return fixture{
+		name:    fixtureName("etcd", ha),
+		kind:    api.ResourceKindEtcd,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindEtcd, db, db.AppBindingMeta().Type(), 2379, ha, ha, objs...),
+	}
+}
+
+func mariadbFixture(ha bool) fixture {
+	replicas := 1
+	if ha {
+		replicas = 3
+	}
+	db := &api.MariaDB{
+		ObjectMeta: fixtureMeta("md", nil),
+		Spec: api.MariaDBSpec{
+			Version:           "10.5.8",
+			Replicas:          pointer.Int32P(int32(replicas)),
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			AuthSecret:        &core.LocalObjectReference{Name: "md-auth"},
+			TerminationPolicy: api.TerminationPolicyDelete,
+		},
+		Status: api.MariaDBStatus{Phase: api.DatabasePhaseReady},
+	}
+	if ha {
+		db.Spec.Monitor = fixtureMonitor()
+	}
+	selectors := db.OffshootSelectors()
+
+	objs := statefulSet(db.OffshootName(), selectors, replicas)
+	objs = append(objs, services(db.ServiceName(), selectors, "db", 3306, podIPs(replicas)...)...)
+	objs = append(objs, authSecret("md-auth"))
+
+	return fixture{
+		name:    fixtureName("mariadb", ha),
+		kind:    api.ResourceKindMariaDB,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindMariaDB, db, db.AppBindingMeta().Type(), 3306, ha, ha, objs...),
+	}
+}
+
+func memcachedFixture(ha bool) fixture {
+	replicas := 1
+	if ha {
+		replicas = 3
+	}
+	db := &api.Memcached{
+		ObjectMeta: fixtureMeta("mc", nil),
+		Spec: api.MemcachedSpec{
+			Version:           "1.5.22",
+			Replicas:          pointer.Int32P(int32(replicas)),
+			TerminationPolicy: api.TerminationPolicyDelete,
+		},
+		Status: api.MemcachedStatus{Phase: api.DatabasePhaseReady},
+	}
+	if ha {
+		db.Spec.Monitor = fixtureMonitor()
+	}
+	selectors := db.OffshootSelectors()
+
+	objs := deployment(db.OffshootName(), selectors, replicas)
+	objs = append(objs, services(db.ServiceName(), selectors, "db", 11211, podIPs(replicas)...)...)
+
+	return fixture{
+		name:    fixtureName("memcached", ha),
+		kind:    api.ResourceKindMemcached,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindMemcached, db, db.AppBindingMeta().Type(), 11211, ha, ha, objs...),
+	}
+}
+
+func mongodbFixture(ha bool) fixture {
+	db := &api.MongoDB{
+		ObjectMeta: fixtureMeta("mg", nil),
+		Spec: api.MongoDBSpec{
+			Version:           "4.2.3",
+			Replicas:          pointer.Int32P(1),
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			AuthSecret:        &core.LocalObjectReference{Name: "mg-auth"},
+			SSLMode:           api.SSLModeDisabled,
+			StorageEngine:     api.StorageEngineWiredTiger,
+			TerminationPolicy: api.TerminationPolicyDelete,
+		},
+		Status: api.MongoDBStatus{Phase: api.DatabasePhaseReady},
+	}
+	selectors := db.OffshootSelectors()
+
+	var objs []runtime.Object
+	if !ha {
+		objs = statefulSet(db.OffshootName(), selectors, 1)
+		objs = append(objs, services(db.ServiceName(), selectors, "db", 27017, podIPs(1)...)...)
+	} else {
+		db.Spec.Replicas = pointer.Int32P(3)
+		db.Spec.ReplicaSet = &api.MongoDBReplicaSet{Name: "rs0"}
+		db.Spec.ClusterAuthMode = api.ClusterAuthModeKeyFile
+		db.Spec.KeyFileSecret = &core.LocalObjectReference{Name: "mg-key"}
+		db.Spec.Monitor = fixtureMonitor()
+		objs = statefulSet(db.OffshootName(), selectors, 3, api.DatabasePodPrimary, api.DatabasePodStandby, api.DatabasePodStandby)
+		objs = append(objs, services(db.ServiceName(), selectors, "db", 27017, podIPs(3)...)...)
+		objs = append(objs, &core.Secret{
+			ObjectMeta: fixtureMeta("mg-key", nil),
+			Type:       core.SecretTypeOpaque,
+			Data:       map[string][]byte{"key.txt": []byte("Xa5dc7vQ2Lh9bN3eXa5dc7vQ2Lh9bN3e")},
+		})
+	}
+	objs = append(objs, authSecret("mg-auth"))
+
+	return fixture{
+		name:    fixtureName("mongodb", ha),
+		kind:    api.ResourceKindMongoDB,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindMongoDB, db, db.AppBindingMeta().Type(), 27017, ha, ha, objs...),
+	}
+}
+
+func mysqlFixture(ha bool) fixture {
+	db := &api.MySQL{
+		ObjectMeta: fixtureMeta("mysql", nil),
+		Spec: api.MySQLSpec{
+			Version:           "8.0.23",
+			Replicas:          pointer.Int32P(1),
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			AuthSecret:        &core.LocalObjectReference{Name: "mysql-auth"},
+			TerminationPolicy: api.TerminationPolicyWipeOut,
+		},
+		Status: api.MySQLStatus{Phase: api.DatabasePhaseReady},
+	}
+	selectors := db.OffshootSelectors()
+
+	var objs []runtime.Object
+	if !ha {
+		objs = statefulSet(db.OffshootName(), selectors, 1)
+		objs = append(objs, services(db.ServiceName(), selectors, "db", 3306, podIPs(1)...)...)
+	} else {
+		mode := api.MySQLClusterModeGroup
+		db.Spec.Replicas = pointer.Int32P(3)
+		db.Spec.Topology = &api.MySQLClusterTopology{
+			Mode:  &mode,
+			Group: &api.MySQLGroupSpec{Name: "dc002fc3-c412-4d18-b1d4-66c1fbfbbc9b"},
+		}
+		db.Spec.TLS = &kmapi.TLSConfig{
+			IssuerRef: &core.TypedLocalObjectReference{
+				APIGroup: pointer.StringP("cert-manager.io"),
+				Kind:     "Issuer",
+				Name:     "mysql-issuer",
+			},
+			Certificates: []kmapi.CertificateSpec{{Alias: "server"}},
+		}
+		db.Spec.Monitor = fixtureMonitor()
+		objs = statefulSet(db.OffshootName(), selectors, 3, api.DatabasePodPrimary, api.DatabasePodStandby, api.DatabasePodStandby)
+		objs = append(objs, services(db.ServiceName(), selectors, "db", 3306, podIPs(1)...)...)
+	}
+	objs = append(objs, authSecret("mysql-auth"))
+
+	return fixture{
+		name:    fixtureName("mysql", ha),
+		kind:    api.ResourceKindMySQL,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindMySQL, db, db.AppBindingMeta().Type(), 3306, ha, ha, objs...),
+	}
+}
+
+func perconaXtraDBFixture(ha bool) fixture {
+	replicas := 1
+	if ha {
+		replicas = 3
+	}
+	db := &api.PerconaXtraDB{
+		ObjectMeta: fixtureMeta("px", nil),
+		Spec: api.PerconaXtraDBSpec{
+			Version:           "5.7-cluster",
+			Replicas:          pointer.Int32P(int32(replicas)),
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			AuthSecret:        &core.LocalObjectReference{Name: "px-auth"},
+			TerminationPolicy: api.TerminationPolicyDelete,
+		},
+		Status: api.PerconaXtraDBStatus{Phase: api.DatabasePhaseReady},
+	}
+	if ha {
+		db.Spec.Monitor = fixtureMonitor()
+	}
+	selectors := db.OffshootSelectors()
+
+	objs := statefulSet(db.OffshootName(), selectors, replicas)
+	objs = append(objs, services(db.ServiceName(), selectors, "db", 3306, podIPs(replicas)...)...)
+	objs = append(objs, authSecret("px-auth"))
+
+	return fixture{
+		name:    fixtureName("perconaxtradb", ha),
+		kind:    api.ResourceKindPerconaXtraDB,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindPerconaXtraDB, db, db.AppBindingMeta().Type(), 3306, ha, ha, objs...),
+	}
+}
+
+func pgbouncerFixture(ha bool) fixture {
+	replicas := 1
+	if ha {
+		replicas = 3
+	}
+	db := &api.PgBouncer{
+		ObjectMeta: fixtureMeta("pb", nil),
+		Spec: api.PgBouncerSpec{
+			Version:  "1.12.0",
+			Replicas: pointer.Int32P(int32(replicas)),
+			Databases: []api.Databases{{
+				Alias:        "postgres",
+				DatabaseName: "postgres",
+				DatabaseRef:  appcat.AppReference{Name: "pg"},
+			}},
+			ConnectionPool: &api.ConnectionPoolConfig{
+				Port:                 pointer.Int32P(5432),
+				PoolMode:             "session",
+				MaxClientConnections: pointer.Int64P(20),
+				DefaultPoolSize:      pointer.Int64P(20),
+				AdminUsers:           []string{"admin"},
+				AuthType:             "md5",
+			},
+			UserListSecretRef: &core.LocalObjectReference{Name: "pb-userlist"},
+		},
+		Status: api.PgBouncerStatus{Phase: api.DatabasePhaseReady},
+	}
+	selectors := db.OffshootSelectors()
+
+	objs := statefulSet(db.OffshootName(), selectors, replicas)
+	objs = append(objs, services(db.ServiceName(), selectors, "db", 5432, podIPs(replicas)...)...)
+	objs = append(objs, &core.Secret{
+		ObjectMeta: fixtureMeta("pb-userlist", nil),
+		Type:       core.SecretTypeOpaque,
+		Data:       map[string][]byte{"userlist.txt": []byte(`"postgres" "Xa5dc7vQ2Lh9bN3e"`)},
+	})
+	if ha {
+		db.Spec.Monitor = fixtureMonitor()
+		pg := &api.Postgres{
+			ObjectMeta: fixtureMeta("pg", nil),
+			Status: api.PostgresStatus{
+				Phase:      api.DatabasePhaseReady,
+				Conditions: []kmapi.Condition{{Type: api.DatabaseReady, Status: core.ConditionTrue}},
+			},
+		}
+		objs = append(objs, pg, &appcat.AppBinding{
+			ObjectMeta: fixtureMeta("pg", nil),
+			Spec:       appcat.AppBindingSpec{Type: pg.AppBindingMeta().Type()},
+		})
+	}
+
+	return fixture{
+		name:    fixtureName("pgbouncer", ha),
+		kind:    api.ResourceKindPgBouncer,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindPgBouncer, db, db.AppBindingMeta().Type(), 5432, ha, ha, objs...),
+	}
+}
+
+func postgresFixture(ha bool) fixture {
+	db := &api.Postgres{
+		ObjectMeta: fixtureMeta("pg", nil),
+		Spec: api.PostgresSpec{
+			Version:           "13.2",
+			Replicas:          pointer.Int32P(1),
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			AuthSecret:        &core.LocalObjectReference{Name: "pg-auth"},
+			ClientAuthMode:    api.ClientAuthModeMD5,
+			SSLMode:           api.PostgresSSLModeDisable,
+			TerminationPolicy: api.TerminationPolicyDelete,
+		},
+		Status: api.PostgresStatus{Phase: api.DatabasePhaseReady},
+	}
+	selectors := db.OffshootSelectors()
+
+	var objs []runtime.Object
+	if !ha {
+		objs = statefulSet(db.OffshootName(), selectors, 1, api.DatabasePodPrimary)
+		objs = append(objs, services(db.ServiceName(), selectors, "api", 5432, podIPs(1)...)...)
+	} else {
+		standby := api.HotPostgresStandbyMode
+		streaming := api.AsynchronousPostgresStreamingMode
+		db.Spec.Replicas = pointer.Int32P(3)
+		db.Spec.StandbyMode = &standby
+		db.Spec.StreamingMode = &streaming
+		db.Spec.LeaderElection = &api.PostgreLeaderElectionConfig{
+			LeaseDurationSeconds:     15,
+			RenewDeadlineSeconds:     10,
+			RetryPeriodSeconds:       2,
+			MaximumLagBeforeFailover: 64 * 1024 * 1024,
+		}
+		db.Spec.Monitor = fixtureMonitor()
+		objs = statefulSet(db.OffshootName(), selectors, 3, api.DatabasePodPrimary, "replica", "replica")
+		objs = append(objs, services(db.ServiceName(), selectors, "api", 5432, podIPs(1)...)...)
+	}
+	objs = append(objs, authSecret("pg-auth"))
+
+	return fixture{
+		name:    fixtureName("postgres", ha),
+		kind:    api.ResourceKindPostgres,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindPostgres, db, db.AppBindingMeta().Type(), 5432, ha, ha, objs...),
+	}
+}
+
+func proxysqlFixture(ha bool) fixture {
+	replicas := 1
+	if ha {
+		replicas = 3
+	}
+	mode := api.LoadBalanceModeGroupReplication
+	db := &api.ProxySQL{
+		ObjectMeta: fixtureMeta("proxy", nil),
+		Spec: api.ProxySQLSpec{
+			Version:  "2.0.4",
+			Replicas: pointer.Int32P(int32(replicas)),
+			Mode:     &mode,
+			Backend: &api.ProxySQLBackendSpec{
+				Ref:      &core.TypedLocalObjectReference{APIGroup: pointer.StringP(api.SchemeGroupVersion.Group), Kind: api.ResourceKindMySQL, Name: "mysql"},
+				Replicas: pointer.Int32P(3),
+			},
+			AuthSecret: &core.LocalObjectReference{Name: "proxy-auth"},
+		},
+		Status: api.ProxySQLStatus{Phase: api.DatabasePhaseReady},
+	}
+	selectors := db.OffshootSelectors()
+
+	objs := statefulSet(db.OffshootName(), selectors, replicas)
+	objs = append(objs, services(db.ServiceName(), selectors, "mysql", 6033, podIPs(replicas)...)...)
+	objs = append(objs, authSecret("proxy-auth"))
+	if ha {
+		db.Spec.Monitor = fixtureMonitor()
+		backend := &api.MySQL{
+			ObjectMeta: fixtureMeta("mysql", nil),
+			Spec:       api.MySQLSpec{Replicas: pointer.Int32P(3)},
+			Status:     api.MySQLStatus{Phase: api.DatabasePhaseReady},
+		}
+		objs = append(objs, backend)
+		objs = append(objs, statefulSet(backend.OffshootName(), backend.OffshootSelectors(), 3, api.DatabasePodPrimary, api.DatabasePodStandby, api.DatabasePodStandby)...)
+	}
+
+	return fixture{
+		name:    fixtureName("proxysql", ha),
+		kind:    api.ResourceKindProxySQL,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindProxySQL, db, db.AppBindingMeta().Type(), 6033, ha, ha, objs...),
+	}
+}
+
+func redisFixture(ha bool) fixture {
+	db := &api.Redis{
+		ObjectMeta: fixtureMeta("rd", nil),
+		Spec: api.RedisSpec{
+			Version:           "6.0.6",
+			Replicas:          pointer.Int32P(1),
+			Mode:              api.RedisModeStandalone,
+			StorageType:       api.StorageTypeDurable,
+			Storage:           fixtureStorage("1Gi"),
+			TerminationPolicy: api.TerminationPolicyHalt,
+		},
+		Status: api.RedisStatus{Phase: api.DatabasePhaseReady},
+	}
+	selectors := db.OffshootSelectors()
+
+	var objs []runtime.Object
+	if !ha {
+		objs = statefulSet(db.OffshootName(), selectors, 1)
+		objs = append(objs, services(db.ServiceName(), selectors, "db", 6379, podIPs(1)...)...)
+	} else {
+		db.Spec.Replicas = nil
+		db.Spec.Mode = api.RedisModeCluster
+		db.Spec.Cluster = &api.RedisClusterSpec{Master: pointer.Int32P(3), Replicas: pointer.Int32P(1)}
+		db.Spec.Monitor = fixtureMonitor()
+		for i := 0; i < 3; i++ {
+			labels := db.OffshootSelectors()
+			labels[api.RedisShardKey] = strconv.Itoa(i)
+			objs = append(objs, statefulSet(db.StatefulSetNameWithShard(i), labels, 2)...)
+		}
+		objs = append(objs, services(db.ServiceName(), selectors, "db", 6379, podIPs(2)...)...)
+	}
+
+	return fixture{
+		name:    fixtureName("redis", ha),
+		kind:    api.ResourceKindRedis,
+		db:      db.Name,
+		cluster: newFixtureCluster(api.ResourceKindRedis, db, db.AppBindingMeta().Type(), 6379, ha, ha, objs...),
+	}
+}
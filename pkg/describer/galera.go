@@ -0,0 +1,47 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"encoding/json"
+
+	configapi "kubedb.dev/apimachinery/apis/config/v1alpha1"
+
+	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
+)
+
+// getGaleraArbitratorConfig returns the GaleraArbitratorConfiguration that the
+// operator stores in the AppBinding parameters of a Galera cluster.
+func getGaleraArbitratorConfig(ab *appcat.AppBinding) (*Section, error) {
+	if ab.Spec.Parameters == nil || len(ab.Spec.Parameters.Raw) == 0 {
+		return nil, nil
+	}
+
+	var cfg configapi.GaleraArbitratorConfiguration
+	if err := json.Unmarshal(ab.Spec.Parameters.Raw, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Kind != configapi.ResourceKindGaleraArbitratorConfiguration {
+		return nil, nil
+	}
+
+	section := &Section{Title: "Galera Arbitrator"}
+	section.addField("Address", "%s", cfg.Address)
+	section.addField("Group", "%s", cfg.Group)
+	section.addField("SST Method", "%s", cfg.SSTMethod)
+	return section, nil
+}
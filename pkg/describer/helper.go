@@ -19,20 +19,27 @@ package describer
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
 
 	"github.com/fatih/camelcase"
+	"gomodules.xyz/pointer"
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/describe"
 	"k8s.io/kubectl/pkg/util/slice"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	core_util "kmodules.xyz/client-go/core/v1"
+	meta_util "kmodules.xyz/client-go/meta"
 	appcat "kmodules.xyz/custom-resources/apis/appcatalog/v1alpha1"
 	mona "kmodules.xyz/monitoring-agent-api/api/v1"
 )
@@ -61,6 +68,31 @@ func describeStorage(st api.StorageType, pvcSpec *core.PersistentVolumeClaimSpec
 	}
 }
 
+// getStorageSection returns the storage of a component of a database that
+// runs its components with separate volumes.
+func getStorageSection(st api.StorageType, pvcSpec *core.PersistentVolumeClaimSpec) *Section {
+	section := &Section{Title: "Storage"}
+	if st == api.StorageTypeEphemeral {
+		section.addField("StorageType", "%s", api.StorageTypeEphemeral)
+	} else {
+		section.addField("StorageType", "%s", api.StorageTypeDurable)
+	}
+	if pvcSpec == nil {
+		section.addNote("No volumes.")
+		return section
+	}
+
+	if pvcSpec.StorageClassName != nil {
+		section.addField("StorageClass", "%s", *pvcSpec.StorageClassName)
+	}
+	val := pvcSpec.Resources.Requests[core.ResourceStorage]
+	section.addField("Capacity", "%s", val.String())
+	if accessModes := getAccessModesAsString(pvcSpec.AccessModes); accessModes != "" {
+		section.addField("Access Modes", "%s", accessModes)
+	}
+	return section
+}
+
 func describeInitialization(init *api.InitSpec, w describe.PrefixWriter) {
 	if init == nil {
 		return
@@ -72,7 +104,7 @@ func describeInitialization(init *api.InitSpec, w describe.PrefixWriter) {
 		describeVolume(LEVEL_2, init.Script.VolumeSource, w)
 	}
 	if init.WaitForInitialRestore {
-		w.Write(LEVEL_1, "WaitForInitialRestore:\t%s\n", init.WaitForInitialRestore)
+		w.Write(LEVEL_1, "WaitForInitialRestore:\t%v\n", init.WaitForInitialRestore)
 	}
 }
 
@@ -99,26 +131,77 @@ func describeMonitor(monitor *mona.AgentSpec, w describe.PrefixWriter) {
 	}
 }
 
-func showAppBinding(ab *appcat.AppBinding, w describe.PrefixWriter) error {
+func describeTLS(dbName string, tls *kmapi.TLSConfig, w describe.PrefixWriter) {
+	if tls == nil {
+		return
+	}
+
 	w.Write(LEVEL_0, "\n")
-	w.Write(LEVEL_0, "AppBinding:\n")
+	w.Write(LEVEL_0, "TLS:\n")
+	if tls.IssuerRef != nil {
+		w.Write(LEVEL_1, "Issuer:\t%s\n", formatTypedLocalObjectReference(tls.IssuerRef))
+	}
+	if len(tls.Certificates) == 0 {
+		w.Write(LEVEL_1, "Certificates:\t%s\n", ValueNone)
+		return
+	}
+	w.Write(LEVEL_1, "Certificates:\n")
+	w.Write(LEVEL_2, "Alias\tSecret\tDuration\tRenewBefore\n")
+	w.Write(LEVEL_2, "-----\t------\t--------\t-----------\n")
+	for _, cert := range tls.Certificates {
+		secretName := certificateSecretName(dbName, cert)
+		certDuration, renewBefore := ValueNone, ValueNone
+		if cert.Duration != nil {
+			certDuration = cert.Duration.Duration.String()
+		}
+		if cert.RenewBefore != nil {
+			renewBefore = cert.RenewBefore.Duration.String()
+		}
+		w.Write(LEVEL_2, "%s\t%s\t%s\t%s\n", cert.Alias, secretName, certDuration, renewBefore)
+	}
+}
+
+// certificateSecretName returns the name of the secret of a certificate. The
+// operator names the secret <db>-<alias>-cert if the secret name is not set.
+func certificateSecretName(dbName string, cert kmapi.CertificateSpec) string {
+	if cert.SecretName != "" {
+		return cert.SecretName
+	}
+	return meta_util.NameWithSuffix(dbName, fmt.Sprintf("%s-cert", cert.Alias))
+}
+
+// getAppBinding returns the AppBinding of a database without the metadata
+// that is populated by the API server.
+func getAppBinding(ab *appcat.AppBinding) (*AppBinding, error) {
 	if ab == nil || ab.Name == "" {
-		w.Write(LEVEL_1, "AppBinding has not been created yet.\n")
-		return nil
+		return &AppBinding{Found: false}, nil
 	}
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ab)
 	if err != nil {
-		return err
-	}
-	printUnstructuredContent(w, LEVEL_1, obj, "",
-		".metadata.managedFields",
-		".metadata.finalizers",
-		".metadata.generation",
-		".metadata.resourceVersion",
-		".metadata.selfLink",
-		".metadata.uid",
-		".metadata.ownerReferences")
-	return nil
+		return nil, err
+	}
+	for _, field := range []string{
+		"managedFields",
+		"finalizers",
+		"generation",
+		"resourceVersion",
+		"selfLink",
+		"uid",
+		"ownerReferences",
+	} {
+		unstructured.RemoveNestedField(obj, "metadata", field)
+	}
+	return &AppBinding{Found: true, Object: obj}, nil
+}
+
+func describeAppBinding(ab *AppBinding, w describe.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "AppBinding:\n")
+	if !ab.Found {
+		w.Write(LEVEL_1, "AppBinding has not been created yet.\n")
+		return
+	}
+	printUnstructuredContent(w, LEVEL_1, ab.Object, "")
 }
 
 func printUnstructuredContent(w describe.PrefixWriter, level int, content map[string]interface{}, skipPrefix string, skip ...string) {
@@ -192,10 +275,13 @@ func smartLabelFor(field string) string {
 	return strings.Join(result, " ")
 }
 
-func showWorkload(client kubernetes.Interface, namespace string, selector labels.Selector, w describe.PrefixWriter) {
+// getWorkloads returns the StatefulSets, Deployments and Services selected by
+// the given selector.
+func getWorkloads(client kubernetes.Interface, namespace string, selector labels.Selector) ([]Workload, []Service) {
 	pc := client.CoreV1().Pods(namespace)
 	opts := metav1.ListOptions{LabelSelector: selector.String()}
 
+	var workloads []Workload
 	if statefulSets, err := client.AppsV1().StatefulSets(namespace).List(context.TODO(), opts); err == nil {
 		for _, s := range statefulSets.Items {
 			selector, err := metav1.LabelSelectorAsSelector(s.Spec.Selector)
@@ -208,7 +294,18 @@ func showWorkload(client kubernetes.Interface, namespace string, selector labels
 				continue
 			}
 
-			describeStatefulSet(&s, running, waiting, succeeded, failed, w)
+			workloads = append(workloads, Workload{
+				Kind:              "StatefulSet",
+				Name:              s.Name,
+				CreationTimestamp: s.CreationTimestamp,
+				Labels:            s.Labels,
+				Annotations:       s.Annotations,
+				Replicas: WorkloadReplicas{
+					Desired: pointer.Int32(s.Spec.Replicas),
+					Total:   s.Status.Replicas,
+				},
+				Pods: PodCounts{Running: running, Waiting: waiting, Succeeded: succeeded, Failed: failed},
+			})
 		}
 	}
 
@@ -224,56 +321,250 @@ func showWorkload(client kubernetes.Interface, namespace string, selector labels
 				continue
 			}
 
-			describeDeployment(&d, running, waiting, succeeded, failed, w)
+			workloads = append(workloads, Workload{
+				Kind:              "Deployment",
+				Name:              d.Name,
+				CreationTimestamp: d.CreationTimestamp,
+				Labels:            d.Labels,
+				Annotations:       d.Annotations,
+				Replicas: WorkloadReplicas{
+					Desired:     pointer.Int32(d.Spec.Replicas),
+					Total:       d.Status.Replicas,
+					Updated:     d.Status.UpdatedReplicas,
+					Available:   d.Status.AvailableReplicas,
+					Unavailable: d.Status.UnavailableReplicas,
+				},
+				Pods: PodCounts{Running: running, Waiting: waiting, Succeeded: succeeded, Failed: failed},
+			})
+		}
+	}
+
+	var services []Service
+	if list, err := client.CoreV1().Services(namespace).List(context.TODO(), opts); err == nil {
+		for i := range list.Items {
+			endpoints, _ := client.CoreV1().Endpoints(namespace).Get(context.TODO(), list.Items[i].Name, metav1.GetOptions{})
+			services = append(services, getService(&list.Items[i], endpoints))
 		}
 	}
 
-	if services, err := client.CoreV1().Services(namespace).List(context.TODO(), opts); err == nil {
-		for _, s := range services.Items {
-			endpoints, _ := client.CoreV1().Endpoints(namespace).Get(context.TODO(), s.Name, metav1.GetOptions{})
-			describeService(&s, endpoints, w)
+	return workloads, services
+}
+
+func getService(service *core.Service, endpoints *core.Endpoints) Service {
+	s := Service{
+		Name:           service.Name,
+		Labels:         service.Labels,
+		Annotations:    service.Annotations,
+		Type:           service.Spec.Type,
+		ClusterIP:      service.Spec.ClusterIP,
+		ExternalIPs:    service.Spec.ExternalIPs,
+		LoadBalancerIP: service.Spec.LoadBalancerIP,
+		ExternalName:   service.Spec.ExternalName,
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			s.LoadBalancerIngress = append(s.LoadBalancerIngress, ingress.IP)
+		} else {
+			s.LoadBalancerIngress = append(s.LoadBalancerIngress, ingress.Hostname)
 		}
 	}
+	for _, sp := range service.Spec.Ports {
+		s.Ports = append(s.Ports, ServicePort{
+			Name:       sp.Name,
+			Port:       sp.Port,
+			Protocol:   sp.Protocol,
+			TargetPort: sp.TargetPort.String(),
+			NodePort:   sp.NodePort,
+			Endpoints:  getEndpointAddresses(endpoints, sp.Name),
+		})
+	}
+	return s
 }
 
-func showSecret(client kubernetes.Interface, namespace string, secrets map[string]*core.LocalObjectReference, w describe.PrefixWriter) {
+// getEndpointAddresses returns the addresses of the endpoints that serve the
+// named port in host:port form.
+func getEndpointAddresses(endpoints *core.Endpoints, portName string) []string {
+	if endpoints == nil {
+		return nil
+	}
+	var addresses []string
+	for _, ss := range endpoints.Subsets {
+		for _, port := range ss.Ports {
+			if port.Name != portName {
+				continue
+			}
+			for _, addr := range ss.Addresses {
+				addresses = append(addresses, net.JoinHostPort(addr.IP, strconv.Itoa(int(port.Port))))
+			}
+		}
+	}
+	return addresses
+}
+
+// getSecrets returns the given secrets by role, with the size of each value
+// instead of the value itself. Secrets that can not be read are skipped.
+func getSecrets(client kubernetes.Interface, namespace string, secrets map[string]*core.LocalObjectReference) []Secret {
 	sc := client.CoreV1().Secrets(namespace)
 
-	for key, sv := range secrets {
-		secret, err := sc.Get(context.TODO(), sv.Name, metav1.GetOptions{})
+	roles := make([]string, 0, len(secrets))
+	for role := range secrets {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	var result []Secret
+	for _, role := range roles {
+		secret, err := sc.Get(context.TODO(), secrets[role].Name, metav1.GetOptions{})
 		if err != nil {
 			continue
 		}
-		describeSecret(secret, key, w)
+		s := Secret{
+			Role:        role,
+			Name:        secret.Name,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+			Type:        secret.Type,
+		}
+		if _, ok := s.Annotations[meta_util.LastAppliedConfigAnnotation]; ok {
+			s.Annotations = make(map[string]string, len(secret.Annotations))
+			for k, v := range secret.Annotations {
+				if k != meta_util.LastAppliedConfigAnnotation {
+					s.Annotations[k] = v
+				}
+			}
+		}
+		for k, v := range secret.Data {
+			s.Data = append(s.Data, SecretKey{Key: k, Size: len(v)})
+		}
+		sort.Slice(s.Data, func(i, j int) bool {
+			return s.Data[i].Key < s.Data[j].Key
+		})
+		result = append(result, s)
 	}
+	return result
 }
 
-func showTopology(client kubernetes.Interface, namespace string, selector labels.Selector, specific map[string]labels.Selector, w describe.PrefixWriter) {
-	w.Write(LEVEL_0, "\n")
-	w.Write(LEVEL_0, "Topology:\n")
-	w.Write(LEVEL_0, "  Type\tPod\tStartTime\tPhase\n")
-	w.Write(LEVEL_0, "  ----\t---\t---------\t-----\n")
+// getTopology returns the pods of a database with the roles given by the
+// selectors in specific that match each pod.
+func getTopology(client kubernetes.Interface, namespace string, selector labels.Selector, specific map[string]labels.Selector) *Topology {
+	topology := &Topology{Title: "Topology"}
 
-	pods, _ := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
 		LabelSelector: selector.String(),
 	})
+	if err != nil {
+		return topology
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
 
-	for _, pod := range pods.Items {
-		types := make([]string, 0)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		roles := make([]string, 0)
 		for key, val := range specific {
 			if val.Matches(labels.Set(pod.Labels)) {
-				types = append(types, key)
+				roles = append(roles, key)
 			}
 		}
-		w.Write(LEVEL_0, "  %s\t%s\t%s\t%s\n",
-			strings.Join(types, "|"),
-			pod.Name,
-			pod.Status.StartTime,
-			pod.Status.Phase,
-		)
+		sort.Strings(roles)
+		topology.Members = append(topology.Members, Member{
+			Name:      pod.Name,
+			Roles:     roles,
+			StartTime: pod.Status.StartTime,
+			Phase:     pod.Status.Phase,
+			Ready:     core_util.IsPodReady(pod),
+		})
 	}
+	return topology
+}
+
+// getClusterMembers returns one member per pod along with the peer address the
+// member uses to reach the others through the governing service.
+func getClusterMembers(client kubernetes.Interface, namespace string, selector labels.Selector, title, governingService string) *Topology {
+	topology := &Topology{Title: title}
 
-	w.Flush()
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return topology
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		topology.Members = append(topology.Members, Member{
+			Name:        pod.Name,
+			PeerAddress: fmt.Sprintf("%s.%s.%s", pod.Name, governingService, namespace),
+			StartTime:   pod.Status.StartTime,
+			Phase:       pod.Status.Phase,
+			Ready:       core_util.IsPodReady(pod),
+		})
+	}
+	return topology
+}
+
+// describeTopology prints one row per member. The Roles and Peer Address
+// columns are only printed when at least one member has them.
+func describeTopology(topology *Topology, w describe.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	w.Write(LEVEL_0, "%s:\n", topology.Title)
+	if len(topology.Members) == 0 {
+		w.Write(LEVEL_1, "No member found.\n")
+		return
+	}
+
+	var withRoles, withPeers bool
+	for _, m := range topology.Members {
+		withRoles = withRoles || len(m.Roles) > 0
+		withPeers = withPeers || m.PeerAddress != ""
+	}
+
+	columns := []string{"Member"}
+	if withRoles {
+		columns = append(columns, "Roles")
+	}
+	if withPeers {
+		columns = append(columns, "Peer Address")
+	}
+	table := newTable(append(columns, "StartTime", "Phase", "Ready")...)
+	for _, m := range topology.Members {
+		row := []interface{}{m.Name}
+		if withRoles {
+			row = append(row, strings.Join(m.Roles, "|"))
+		}
+		if withPeers {
+			row = append(row, m.PeerAddress)
+		}
+		table.addRow(append(row, timeToString(m.StartTime), m.Phase, m.Ready)...)
+	}
+	describeTable(LEVEL_1, table, w)
+}
+
+// getPrimaryPods returns the names of the pods selected by the given offshoot
+// selectors that are labeled as primary by the operator.
+func getPrimaryPods(client kubernetes.Interface, namespace string, selectors map[string]string) ([]string, error) {
+	set := labels.Set{}
+	for k, v := range selectors {
+		set[k] = v
+	}
+	set[api.LabelRole] = api.DatabasePodPrimary
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: set.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	return names, nil
 }
 
 func getAccessModesAsString(modes []core.PersistentVolumeAccessMode) string {
@@ -309,3 +600,15 @@ func containsAccessMode(modes []core.PersistentVolumeAccessMode, mode core.Persi
 	}
 	return false
 }
+
+func getConditions(conditions []kmapi.Condition) *Section {
+	section := &Section{Title: "Conditions"}
+	if len(conditions) == 0 {
+		return section
+	}
+	section.Table = newTable("Type", "Status", "Reason", "LastTransitionTime", "Message")
+	for _, c := range conditions {
+		section.Table.addRow(c.Type, c.Status, c.Reason, timeToString(&c.LastTransitionTime), c.Message)
+	}
+	return section
+}
@@ -0,0 +1,150 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
+
+	"gomodules.xyz/pointer"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/describe"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	"kmodules.xyz/client-go/discovery"
+	appcat_cs "kmodules.xyz/custom-resources/client/clientset/versioned"
+	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
+	stash "stash.appscode.dev/apimachinery/client/clientset/versioned"
+)
+
+type MariaDBDescriber struct {
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
+}
+
+func (d *MariaDBDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
+	if err != nil {
+		return "", err
+	}
+	return RenderText(desc)
+}
+
+func (d *MariaDBDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.MariaDBs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.SelectorFromSet(item.OffshootSelectors())
+
+	var events []Event
+	if describerSettings.ShowEvents {
+		events, err = getEvents(d.client, item.Namespace, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d.describeMariaDB(item, selector, events)
+}
+
+func (d *MariaDBDescriber) describeMariaDB(item *api.MariaDB, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindMariaDB), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	desc.addField("RequireSSL", "%v", item.Spec.RequireSSL)
+	desc.setStorage(item.Spec.StorageType, item.Spec.Storage)
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+	desc.addField("Halted", "%v", item.Spec.Halted)
+	desc.addField("Termination Policy", "%v", item.Spec.TerminationPolicy)
+
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
+
+	secrets := make(map[string]*core.LocalObjectReference)
+	if item.Spec.AuthSecret != nil {
+		secrets["Auth"] = item.Spec.AuthSecret
+	}
+	if item.Spec.ConfigSecret != nil {
+		secrets["Config"] = item.Spec.ConfigSecret
+	}
+	desc.Secrets = getSecrets(d.client, item.Namespace, secrets)
+
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
+
+	if item.IsCluster() {
+		desc.Topology = getClusterMembers(d.client, item.Namespace, selector, "Galera Cluster", item.GoverningServiceName())
+	}
+
+	desc.Monitor = item.Spec.Monitor
+	desc.Init = item.Spec.Init
+
+	var err error
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindMariaDB, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindMariaDB, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return nil, err
+	}
+
+	// Galera arbitrator configuration used by the backup process
+	if item.IsCluster() && ab != nil {
+		section, err := getGaleraArbitratorConfig(ab)
+		if err != nil {
+			return nil, err
+		}
+		desc.addSection(section)
+	}
+
+	// Backup information
+	if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		desc.Backup, err = getBackup(d.stash, ab)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ab != nil {
+		desc.AppBinding, err = getAppBinding(ab)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	desc.Events = events
+	return desc, nil
+}
@@ -18,17 +18,15 @@ package describer
 
 import (
 	"context"
-	"io"
 
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
-	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
 
 	"gomodules.xyz/pointer"
-	core "k8s.io/api/core/v1"
 	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/describe"
 	kmapi "kmodules.xyz/client-go/api/v1"
@@ -39,80 +37,88 @@ import (
 )
 
 type MemcachedDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha2Interface
-	stash  stash.Interface
-	appcat appcat_cs.Interface
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
 }
 
 func (d *MemcachedDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.Memcacheds(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
 	if err != nil {
 		return "", err
 	}
+	return RenderText(desc)
+}
+
+func (d *MemcachedDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.Memcacheds(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
 
-	var events *core.EventList
+	var events []Event
 	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
+		events, err = getEvents(d.client, item.Namespace, item)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
 	return d.describeMemcached(item, selector, events)
 }
 
-func (d *MemcachedDescriber) describeMemcached(item *api.Memcached, selector labels.Selector, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := describe.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", pointer.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
+func (d *MemcachedDescriber) describeMemcached(item *api.Memcached, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindMemcached), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+	desc.addField("Halted", "%v", item.Spec.Halted)
+	desc.addField("Termination Policy", "%v", item.Spec.TerminationPolicy)
 
-		w.Write(LEVEL_0, "Paused:\t%v\n", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
-		w.Write(LEVEL_0, "Halted:\t%v\n", item.Spec.Halted)
-		w.Write(LEVEL_0, "Termination Policy:\t%v\n", item.Spec.TerminationPolicy)
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
 
-		showWorkload(d.client, item.Namespace, selector, w)
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+	desc.Monitor = item.Spec.Monitor
 
-		ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
-		if err != nil && !kerr.IsNotFound(err) {
-			return err
-		}
+	var err error
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindMemcached, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindMemcached, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
 
-		// Show Backup information
-		if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
-			err = showBackups(d.stash, ab, w)
-			if err != nil {
-				return err
-			}
-		}
+	ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return nil, err
+	}
 
-		// Show AppBinding
-		if ab != nil {
-			err = showAppBinding(ab, w)
-			if err != nil {
-				return err
-			}
+	// Backup information
+	if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		desc.Backup, err = getBackup(d.stash, ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		if events != nil {
-			DescribeEvents(events, w)
+	if ab != nil {
+		desc.AppBinding, err = getAppBinding(ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		return nil
-	})
+	desc.Events = events
+	return desc, nil
 }
@@ -18,10 +18,10 @@ package describer
 
 import (
 	"context"
-	"io"
+	"fmt"
+	"sort"
 
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
-	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
 
 	"gomodules.xyz/pointer"
@@ -29,9 +29,11 @@ import (
 	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/describe"
 	kmapi "kmodules.xyz/client-go/api/v1"
+	core_util "kmodules.xyz/client-go/core/v1"
 	"kmodules.xyz/client-go/discovery"
 	appcat_cs "kmodules.xyz/custom-resources/client/clientset/versioned"
 	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
@@ -39,90 +41,216 @@ import (
 )
 
 type MongoDBDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha2Interface
-	stash  stash.Interface
-	appcat appcat_cs.Interface
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
 }
 
 func (d *MongoDBDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.MongoDBs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
 	if err != nil {
 		return "", err
 	}
+	return RenderText(desc)
+}
+
+func (d *MongoDBDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.MongoDBs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
 
-	var events *core.EventList
+	var events []Event
 	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
+		events, err = getEvents(d.client, item.Namespace, item)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
 	return d.describeMongoDB(item, selector, events)
 }
 
-func (d *MongoDBDescriber) describeMongoDB(item *api.MongoDB, selector labels.Selector, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := describe.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", pointer.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
+func (d *MongoDBDescriber) describeMongoDB(item *api.MongoDB, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindMongoDB), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	if item.Spec.ShardTopology == nil {
+		desc.setStorage(item.Spec.StorageType, item.Spec.Storage)
+	}
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+	desc.addField("Halted", "%v", item.Spec.Halted)
+	desc.addField("Termination Policy", "%v", item.Spec.TerminationPolicy)
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
 
-		w.Write(LEVEL_0, "Paused:\t%v\n", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
-		w.Write(LEVEL_0, "Halted:\t%v\n", item.Spec.Halted)
-		w.Write(LEVEL_0, "Termination Policy:\t%v\n", item.Spec.TerminationPolicy)
+	secrets := make(map[string]*core.LocalObjectReference)
+	if item.Spec.AuthSecret != nil {
+		secrets["Auth"] = item.Spec.AuthSecret
+	}
+	if item.Spec.KeyFileSecret != nil {
+		secrets["KeyFile"] = item.Spec.KeyFileSecret
+	}
+	desc.Secrets = getSecrets(d.client, item.Namespace, secrets)
 
-		showWorkload(d.client, item.Namespace, selector, w)
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
 
-		secrets := make(map[string]*core.LocalObjectReference)
-		if item.Spec.AuthSecret != nil {
-			secrets["Auth"] = item.Spec.AuthSecret
-		}
-		showSecret(d.client, item.Namespace, secrets, w)
+	switch {
+	case item.Spec.ShardTopology != nil:
+		desc.addSection(getShardTopology(d.client, item))
+	case item.Spec.ReplicaSet != nil:
+		desc.addSection(getReplicaSet(item))
+		desc.Topology = getTopology(d.client, item.Namespace, selector, map[string]labels.Selector{
+			api.DatabasePodPrimary: labels.SelectorFromSet(map[string]string{api.LabelRole: api.DatabasePodPrimary}),
+			api.DatabasePodStandby: labels.SelectorFromSet(map[string]string{api.LabelRole: api.DatabasePodStandby}),
+		})
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+	desc.Monitor = item.Spec.Monitor
+	desc.Init = item.Spec.Init
 
-		// Show initialization information
-		describeInitialization(item.Spec.Init, w)
+	var err error
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindMongoDB, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindMongoDB, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
 
-		ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
-		if err != nil && !kerr.IsNotFound(err) {
-			return err
+	ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return nil, err
+	}
+
+	// Backup information
+	if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		desc.Backup, err = getBackup(d.stash, ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Show Backup information
-		if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
-			err = showBackups(d.stash, ab, w)
-			if err != nil {
-				return err
-			}
+	if ab != nil {
+		desc.AppBinding, err = getAppBinding(ab)
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	desc.Events = events
+	return desc, nil
+}
 
-		// Show AppBinding
-		if ab != nil {
-			err = showAppBinding(ab, w)
-			if err != nil {
-				return err
+// getReplicaSet returns the replica set configuration of a MongoDB that runs
+// in ReplicaSet mode.
+func getReplicaSet(item *api.MongoDB) *Section {
+	section := &Section{Title: "Replica Set"}
+	section.addField("Name", "%s", item.RepSetName())
+	addMongoDBClusterFields(section, item)
+	return section
+}
+
+// getShardTopology groups the pods of a sharded MongoDB by shard, config
+// server and mongos.
+func getShardTopology(client kubernetes.Interface, item *api.MongoDB) *Section {
+	topology := item.Spec.ShardTopology
+	section := &Section{Title: "Shard Topology"}
+	addMongoDBClusterFields(section, item)
+
+	for i := int32(0); i < topology.Shard.Shards; i++ {
+		shard := getMongoDBReplicaSetNode(client, item.Namespace, item.ShardSelectors(i), item.ShardNodeName(i), item.ShardRepSetName(i), topology.Shard.Replicas)
+		shard.Title = fmt.Sprintf("Shard %d", i)
+		shard.addSection(getStorageSection(item.Spec.StorageType, topology.Shard.Storage))
+		section.addSection(shard)
+	}
+
+	configSvr := getMongoDBReplicaSetNode(client, item.Namespace, item.ConfigSvrSelectors(), item.ConfigSvrNodeName(), item.ConfigSvrRepSetName(), topology.ConfigServer.Replicas)
+	configSvr.Title = "Config Server"
+	configSvr.addSection(getStorageSection(item.Spec.StorageType, topology.ConfigServer.Storage))
+	section.addSection(configSvr)
+
+	mongos := &Section{Title: "Mongos"}
+	mongos.addField("Deployment", "%s", item.MongosNodeName())
+	mongos.addField("Replicas", "%d  total", topology.Mongos.Replicas)
+	mongos.Table, _ = getMongoDBMembers(client, item.Namespace, item.MongosSelectors(), false)
+	section.addSection(mongos)
+
+	return section
+}
+
+// getMongoDBReplicaSetNode returns the StatefulSet and the members of a shard
+// or of the config server replica set. The section notes when none of the
+// running members is labeled as primary.
+func getMongoDBReplicaSetNode(client kubernetes.Interface, namespace string, selectors map[string]string, stsName, repSetName string, replicas int32) *Section {
+	section := &Section{}
+	section.addField("StatefulSet", "%s", stsName)
+	section.addField("Replica Set", "%s", repSetName)
+	section.addField("Replicas", "%d  total", replicas)
+
+	var primaries int
+	section.Table, primaries = getMongoDBMembers(client, namespace, selectors, true)
+	if len(section.Table.Rows) > 0 && primaries == 0 {
+		section.addNote("No primary found.")
+	}
+	return section
+}
+
+// getMongoDBMembers returns a table of the selected pods and the number of
+// pods that are labeled as primary. The role of each pod is taken from its
+// role label if withRoles is true.
+func getMongoDBMembers(client kubernetes.Interface, namespace string, selectors map[string]string, withRoles bool) (*Table, int) {
+	columns := []string{"Member"}
+	if withRoles {
+		columns = append(columns, "Role")
+	}
+	table := newTable(append(columns, "StartTime", "Phase", "Ready")...)
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selectors).String(),
+	})
+	if err != nil {
+		return table, 0
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	var primaries int
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		row := []interface{}{pod.Name}
+		if withRoles {
+			role, ok := pod.Labels[api.LabelRole]
+			if !ok {
+				role = ValueNone
 			}
+			if role == api.DatabasePodPrimary {
+				primaries++
+			}
+			row = append(row, role)
 		}
-		if events != nil {
-			DescribeEvents(events, w)
-		}
+		table.addRow(append(row, timeToString(pod.Status.StartTime), pod.Status.Phase, core_util.IsPodReady(pod))...)
+	}
+	return table, primaries
+}
 
-		return nil
-	})
+func addMongoDBClusterFields(section *Section, item *api.MongoDB) {
+	section.addField("ClusterAuthMode", "%s", item.Spec.ClusterAuthMode)
+	section.addField("SSLMode", "%s", item.Spec.SSLMode)
+	section.addField("StorageEngine", "%s", item.Spec.StorageEngine)
+	if item.Spec.KeyFileSecret != nil {
+		section.addField("KeyFileSecret", "%s", item.Spec.KeyFileSecret.Name)
+	} else {
+		section.addField("KeyFileSecret", "%s", ValueNone)
+	}
 }
@@ -18,10 +18,9 @@ package describer
 
 import (
 	"context"
-	"io"
+	"strings"
 
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
-	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
 
 	"gomodules.xyz/pointer"
@@ -29,6 +28,7 @@ import (
 	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/describe"
 	kmapi "kmodules.xyz/client-go/api/v1"
@@ -39,91 +39,143 @@ import (
 )
 
 type MySQLDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha2Interface
-	stash  stash.Interface
-	appcat appcat_cs.Interface
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
 }
 
 func (d *MySQLDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.MySQLs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
 	if err != nil {
 		return "", err
 	}
+	return RenderText(desc)
+}
+
+func (d *MySQLDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.MySQLs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
 
-	var events *core.EventList
+	var events []Event
 	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
+		events, err = getEvents(d.client, item.Namespace, item)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
 	return d.describeMySQL(item, selector, events)
 }
 
-func (d *MySQLDescriber) describeMySQL(item *api.MySQL, selector labels.Selector, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := describe.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", pointer.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
+func (d *MySQLDescriber) describeMySQL(item *api.MySQL, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindMySQL), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	desc.setStorage(item.Spec.StorageType, item.Spec.Storage)
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+	desc.addField("Halted", "%v", item.Spec.Halted)
+	desc.addField("Termination Policy", "%v", item.Spec.TerminationPolicy)
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
 
-		w.Write(LEVEL_0, "Paused:\t%v\n", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
-		w.Write(LEVEL_0, "Halted:\t%v\n", item.Spec.Halted)
-		w.Write(LEVEL_0, "Termination Policy:\t%v\n", item.Spec.TerminationPolicy)
+	secrets := make(map[string]*core.LocalObjectReference)
+	if item.Spec.AuthSecret != nil {
+		secrets["Auth"] = item.Spec.AuthSecret
+	}
+	desc.Secrets = getSecrets(d.client, item.Namespace, secrets)
 
-		showWorkload(d.client, item.Namespace, selector, w)
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
 
-		secrets := make(map[string]*core.LocalObjectReference)
-		if item.Spec.AuthSecret != nil {
-			secrets["Auth"] = item.Spec.AuthSecret
-		}
-		showSecret(d.client, item.Namespace, secrets, w)
+	if item.UsesGroupReplication() {
+		desc.Topology = getTopology(d.client, item.Namespace, selector, map[string]labels.Selector{
+			api.DatabasePodPrimary: labels.SelectorFromSet(map[string]string{api.LabelRole: api.DatabasePodPrimary}),
+			api.DatabasePodStandby: labels.SelectorFromSet(map[string]string{api.LabelRole: api.DatabasePodStandby}),
+		})
+		desc.addSection(getMySQLGroup(item, desc.Topology))
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+	desc.Monitor = item.Spec.Monitor
+	desc.Init = item.Spec.Init
 
-		// Show initialization information
-		describeInitialization(item.Spec.Init, w)
+	var err error
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindMySQL, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindMySQL, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
 
-		ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
-		if err != nil && !kerr.IsNotFound(err) {
-			return err
-		}
+	ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return nil, err
+	}
 
-		// Show Backup information
-		if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
-			err = showBackups(d.stash, ab, w)
-			if err != nil {
-				return err
-			}
+	// Backup information
+	if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		desc.Backup, err = getBackup(d.stash, ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Show AppBinding
-		if ab != nil {
-			err = showAppBinding(ab, w)
-			if err != nil {
-				return err
-			}
+	if ab != nil {
+		desc.AppBinding, err = getAppBinding(ab)
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	desc.Events = events
+	return desc, nil
+}
 
-		if events != nil {
-			DescribeEvents(events, w)
+// getMySQLGroup returns the group replication settings of a MySQL cluster.
+// It warns when the role labels of the members do not match the group mode.
+func getMySQLGroup(item *api.MySQL, topology *Topology) *Section {
+	section := &Section{Title: "Group Replication"}
+	section.addField("Cluster Mode", "%s", *item.Spec.Topology.Mode)
+
+	groupMode := api.MySQLGroupModeSinglePrimary
+	if group := item.Spec.Topology.Group; group != nil {
+		if group.Name != "" {
+			section.addField("Group Name", "%s", group.Name)
+		} else {
+			section.addField("Group Name", "%s", ValueNone)
+		}
+		if group.Mode != nil {
+			groupMode = *group.Mode
 		}
+	}
+	section.addField("Group Mode", "%s", groupMode)
 
-		return nil
-	})
+	if len(topology.Members) == 0 {
+		return section
+	}
+	var primaries []string
+	for _, m := range topology.Members {
+		for _, role := range m.Roles {
+			if role == api.DatabasePodPrimary {
+				primaries = append(primaries, m.Name)
+			}
+		}
+	}
+	switch {
+	case len(primaries) == 0:
+		section.addNote("No primary found.")
+	case len(primaries) > 1 && groupMode == api.MySQLGroupModeSinglePrimary:
+		section.addNote("Found %d primaries in %s mode: %s.", len(primaries), groupMode, strings.Join(primaries, ", "))
+	}
+	return section
 }
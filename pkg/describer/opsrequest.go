@@ -0,0 +1,590 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	opsapi "kubedb.dev/apimachinery/apis/ops/v1alpha1"
+	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
+
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/describe"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+type opsRequestKind struct {
+	plural       string
+	databaseKind string
+}
+
+// opsRequestKinds lists the OpsRequest kinds from ops.kubedb.com/v1alpha1 with
+// their resource name and the kind of database they operate on.
+var opsRequestKinds = map[string]opsRequestKind{
+	opsapi.ResourceKindElasticsearchOpsRequest: {opsapi.ResourcePluralElasticsearchOpsRequest, api.ResourceKindElasticsearch},
+	opsapi.ResourceKindEtcdOpsRequest:          {opsapi.ResourcePluralEtcdOpsRequest, api.ResourceKindEtcd},
+	opsapi.ResourceKindMariaDBOpsRequest:       {opsapi.ResourcePluralMariaDBOpsRequest, api.ResourceKindMariaDB},
+	opsapi.ResourceKindMemcachedOpsRequest:     {opsapi.ResourcePluralMemcachedOpsRequest, api.ResourceKindMemcached},
+	opsapi.ResourceKindMongoDBOpsRequest:       {opsapi.ResourcePluralMongoDBOpsRequest, api.ResourceKindMongoDB},
+	opsapi.ResourceKindMySQLOpsRequest:         {opsapi.ResourcePluralMySQLOpsRequest, api.ResourceKindMySQL},
+	opsapi.ResourceKindPerconaXtraDBOpsRequest: {opsapi.ResourcePluralPerconaXtraDBOpsRequest, api.ResourceKindPerconaXtraDB},
+	opsapi.ResourceKindPgBouncerOpsRequest:     {opsapi.ResourcePluralPgBouncerOpsRequest, api.ResourceKindPgBouncer},
+	opsapi.ResourceKindPostgresOpsRequest:      {opsapi.ResourcePluralPostgresOpsRequest, api.ResourceKindPostgres},
+	opsapi.ResourceKindProxySQLOpsRequest:      {opsapi.ResourcePluralProxySQLOpsRequest, api.ResourceKindProxySQL},
+	opsapi.ResourceKindRedisOpsRequest:         {opsapi.ResourcePluralRedisOpsRequest, api.ResourceKindRedis},
+}
+
+// opsRequest holds the fields that every OpsRequest kind has in common.
+type opsRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		DatabaseRef core.LocalObjectReference `json:"databaseRef"`
+		Type        opsapi.OpsRequestType     `json:"type"`
+		Timeout     *metav1.Duration          `json:"timeout,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Phase      opsapi.OpsRequestPhase `json:"phase,omitempty"`
+		Conditions []kmapi.Condition      `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+// getOpsRequests returns the OpsRequests that target the given database, the
+// most recent one first. It returns nil if the OpsRequest CRDs are not installed.
+func getOpsRequests(dc dynamic.Interface, databaseKind, namespace, name string) ([]OpsRequestRef, error) {
+	var plural string
+	for _, info := range opsRequestKinds {
+		if info.databaseKind == databaseKind {
+			plural = info.plural
+		}
+	}
+	if plural == "" {
+		return nil, nil
+	}
+
+	list, err := dc.Resource(opsapi.SchemeGroupVersion.WithResource(plural)).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if kerr.IsNotFound(err) {
+		// ops.kubedb.com CRDs are not installed
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	requests := make([]OpsRequestRef, 0)
+	for _, u := range list.Items {
+		var req opsRequest
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &req); err != nil {
+			return nil, err
+		}
+		if req.Spec.DatabaseRef.Name == name {
+			requests = append(requests, OpsRequestRef{
+				Name:              req.Name,
+				Type:              string(req.Spec.Type),
+				Phase:             string(req.Status.Phase),
+				CreationTimestamp: req.CreationTimestamp,
+			})
+		}
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[j].CreationTimestamp.Before(&requests[i].CreationTimestamp)
+	})
+	return requests, nil
+}
+
+func describeOpsRequestRefs(requests []OpsRequestRef, w describe.PrefixWriter) {
+	w.Write(LEVEL_0, "\n")
+	if len(requests) == 0 {
+		w.Write(LEVEL_0, "OpsRequests:\t%s\n", ValueNone)
+		return
+	}
+
+	w.Write(LEVEL_0, "OpsRequests:\n")
+	w.Write(LEVEL_1, "Name\tType\tPhase\tAge\n")
+	w.Write(LEVEL_1, "----\t----\t-----\t---\n")
+	for _, req := range requests {
+		age := duration.HumanDuration(timeNow().Sub(req.CreationTimestamp.Time))
+		w.Write(LEVEL_1, "%s\t%s\t%s\t%s\n", req.Name, req.Type, req.Phase, age)
+	}
+}
+
+type OpsRequestDescriber struct {
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	kind    string
+}
+
+func (d *OpsRequestDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
+	if err != nil {
+		return "", err
+	}
+	return RenderText(desc)
+}
+
+func (d *OpsRequestDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	info, ok := opsRequestKinds[d.kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown OpsRequest kind %s", d.kind)
+	}
+
+	u, err := d.dynamic.Resource(opsapi.SchemeGroupVersion.WithResource(info.plural)).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var item opsRequest
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &item); err != nil {
+		return nil, err
+	}
+	obj, err := scheme.Scheme.New(opsapi.SchemeGroupVersion.WithKind(d.kind))
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), obj); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	if describerSettings.ShowEvents {
+		events, err = getEvents(d.client, item.Namespace, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d.describeOpsRequest(&item, info.databaseKind, obj, events), nil
+}
+
+func (d *OpsRequestDescriber) describeOpsRequest(item *opsRequest, databaseKind string, obj runtime.Object, events []Event) *Description {
+	desc := newDescription(opsapi.SchemeGroupVersion.WithKind(d.kind), item.ObjectMeta)
+	desc.addField("Type", "%s", item.Spec.Type)
+	desc.addField("Database", "%s/%s", databaseKind, item.Spec.DatabaseRef.Name)
+	desc.addField("Status", "%s", item.Status.Phase)
+	if item.Spec.Timeout != nil {
+		desc.addField("Timeout", "%s", item.Spec.Timeout.Duration)
+	}
+
+	describeOpsRequestSpec(item.Spec.Type, obj, desc)
+
+	desc.addSection(getOpsRequestTimeline(item.CreationTimestamp, item.Status.Phase, item.Status.Conditions))
+
+	desc.Events = events
+	return desc
+}
+
+// getOpsRequestTimeline lists the status conditions of an OpsRequest in the
+// order they happened, with the time each step took after the previous one.
+// While the request is still running, the time spent since the last recorded
+// step is shown as well, which points out where a long operation is stuck.
+func getOpsRequestTimeline(created metav1.Time, phase opsapi.OpsRequestPhase, conditions []kmapi.Condition) *Section {
+	section := &Section{Title: "Timeline"}
+	if len(conditions) == 0 {
+		return section
+	}
+
+	steps := make([]kmapi.Condition, len(conditions))
+	copy(steps, conditions)
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].LastTransitionTime.Before(&steps[j].LastTransitionTime)
+	})
+
+	section.Table = newTable("#", "Step", "Status", "Time", "Duration", "Reason", "Message")
+	prev := created
+	for i, c := range steps {
+		section.Table.addRow(
+			i+1,
+			c.Type,
+			c.Status,
+			timeToString(&c.LastTransitionTime),
+			formatStepDuration(prev, c.LastTransitionTime),
+			c.Reason,
+			strings.TrimSpace(c.Message),
+		)
+		prev = c.LastTransitionTime
+	}
+
+	switch phase {
+	case opsapi.OpsRequestPhaseSuccessful, opsapi.OpsRequestPhaseFailed, opsapi.OpsRequestDenied:
+		section.addField("Total Duration", "%s", formatStepDuration(created, prev))
+	default:
+		section.addField("Waiting On Next Step For", "%s", duration.HumanDuration(timeNow().Sub(prev.Time)))
+	}
+	return section
+}
+
+func formatStepDuration(from, to metav1.Time) string {
+	if from.IsZero() || to.IsZero() || to.Before(&from) {
+		return "-"
+	}
+	return duration.HumanDuration(to.Sub(from.Time))
+}
+
+// describeOpsRequestSpec adds the part of the spec that belongs to the type of
+// the OpsRequest.
+func describeOpsRequestSpec(opsType opsapi.OpsRequestType, obj runtime.Object, desc *Description) {
+	switch req := obj.(type) {
+	case *opsapi.ElasticsearchOpsRequest:
+		describeElasticsearchOpsRequestSpec(opsType, &req.Spec, desc)
+	case *opsapi.EtcdOpsRequest:
+		if req.Spec.Upgrade != nil {
+			desc.addSection(getOpsUpgrade(req.Spec.Upgrade.TargetVersion))
+		}
+	case *opsapi.MariaDBOpsRequest:
+		describeMariaDBOpsRequestSpec(opsType, &req.Spec, desc)
+	case *opsapi.MemcachedOpsRequest:
+		if req.Spec.Upgrade != nil {
+			desc.addSection(getOpsUpgrade(req.Spec.Upgrade.TargetVersion))
+		}
+	case *opsapi.MongoDBOpsRequest:
+		describeMongoDBOpsRequestSpec(opsType, &req.Spec, desc)
+	case *opsapi.MySQLOpsRequest:
+		describeMySQLOpsRequestSpec(opsType, &req.Spec, desc)
+	case *opsapi.PerconaXtraDBOpsRequest:
+		if req.Spec.Upgrade != nil {
+			desc.addSection(getOpsUpgrade(req.Spec.Upgrade.TargetVersion))
+		}
+	case *opsapi.PgBouncerOpsRequest:
+		if req.Spec.Upgrade != nil {
+			desc.addSection(getOpsUpgrade(req.Spec.Upgrade.TargetVersion))
+		}
+	case *opsapi.PostgresOpsRequest:
+		describePostgresOpsRequestSpec(opsType, &req.Spec, desc)
+	case *opsapi.ProxySQLOpsRequest:
+		if req.Spec.Upgrade != nil {
+			desc.addSection(getOpsUpgrade(req.Spec.Upgrade.TargetVersion))
+		}
+	case *opsapi.RedisOpsRequest:
+		describeRedisOpsRequestSpec(opsType, &req.Spec, desc)
+	}
+}
+
+func describeElasticsearchOpsRequestSpec(opsType opsapi.OpsRequestType, spec *opsapi.ElasticsearchOpsRequestSpec, desc *Description) {
+	switch {
+	case opsType == opsapi.OpsRequestTypeUpgrade && spec.Upgrade != nil:
+		desc.addSection(getOpsUpgrade(spec.Upgrade.TargetVersion))
+	case opsType == opsapi.OpsRequestTypeHorizontalScaling && spec.HorizontalScaling != nil:
+		var values []opsComponentValue
+		values = appendInt32Value(values, "Node", spec.HorizontalScaling.Node)
+		if t := spec.HorizontalScaling.Topology; t != nil {
+			values = appendInt32Value(values, "Master", t.Master)
+			values = appendInt32Value(values, "Ingest", t.Ingest)
+			values = appendInt32Value(values, "Data", t.Data)
+		}
+		desc.addSection(getOpsComponents("Horizontal Scaling", "Replicas", values))
+	case opsType == opsapi.OpsRequestTypeVerticalScaling && spec.VerticalScaling != nil:
+		var resources []opsComponentResources
+		resources = appendResources(resources, "Node", spec.VerticalScaling.Node)
+		if t := spec.VerticalScaling.Topology; t != nil {
+			resources = appendResources(resources, "Master", t.Master)
+			resources = appendResources(resources, "Ingest", t.Ingest)
+			resources = appendResources(resources, "Data", t.Data)
+		}
+		resources = appendResources(resources, "Exporter", spec.VerticalScaling.Exporter)
+		desc.addSection(getOpsResources(resources))
+	case opsType == opsapi.OpsRequestTypeVolumeExpansion && spec.VolumeExpansion != nil:
+		var values []opsComponentValue
+		values = appendQuantityValue(values, "Node", spec.VolumeExpansion.Node)
+		if t := spec.VolumeExpansion.Topology; t != nil {
+			values = appendQuantityValue(values, "Master", t.Master)
+			values = appendQuantityValue(values, "Ingest", t.Ingest)
+			values = appendQuantityValue(values, "Data", t.Data)
+		}
+		desc.addSection(getOpsComponents("Volume Expansion", "Size", values))
+	case opsType == opsapi.OpsRequestTypeReconfigureTLSs && spec.TLS != nil:
+		desc.addSection(getOpsTLS(spec.TLS))
+	}
+}
+
+func describeMariaDBOpsRequestSpec(opsType opsapi.OpsRequestType, spec *opsapi.MariaDBOpsRequestSpec, desc *Description) {
+	switch {
+	case opsType == opsapi.OpsRequestTypeUpgrade && spec.Upgrade != nil:
+		desc.addSection(getOpsUpgrade(spec.Upgrade.TargetVersion))
+	case opsType == opsapi.OpsRequestTypeHorizontalScaling && spec.HorizontalScaling != nil:
+		values := appendInt32Value(nil, "Member", spec.HorizontalScaling.Member)
+		section := getOpsComponents("Horizontal Scaling", "Replicas", values)
+		if spec.HorizontalScaling.MemberWeight != 0 {
+			section.addField("Member Weight", "%d", spec.HorizontalScaling.MemberWeight)
+		}
+		desc.addSection(section)
+	case opsType == opsapi.OpsRequestTypeVerticalScaling && spec.VerticalScaling != nil:
+		resources := appendResources(nil, "MariaDB", spec.VerticalScaling.MariaDB)
+		resources = appendResources(resources, "Exporter", spec.VerticalScaling.Exporter)
+		desc.addSection(getOpsResources(resources))
+	case opsType == opsapi.OpsRequestTypeVolumeExpansion && spec.VolumeExpansion != nil:
+		values := appendQuantityValue(nil, "MariaDB", spec.VolumeExpansion.MariaDB)
+		desc.addSection(getOpsComponents("Volume Expansion", "Size", values))
+	case opsType == opsapi.OpsRequestTypeReconfigure && spec.Configuration != nil:
+		c := spec.Configuration
+		desc.addSection(getOpsConfiguration("MariaDB", c.ConfigSecret, c.InlineConfig, c.RemoveCustomConfig))
+	case opsType == opsapi.OpsRequestTypeReconfigureTLSs && spec.TLS != nil:
+		section := getOpsTLS(&spec.TLS.TLSSpec)
+		if spec.TLS.RequireSSL != nil {
+			section.addField("RequireSSL", "%v", *spec.TLS.RequireSSL)
+		}
+		desc.addSection(section)
+	}
+}
+
+func describeMongoDBOpsRequestSpec(opsType opsapi.OpsRequestType, spec *opsapi.MongoDBOpsRequestSpec, desc *Description) {
+	switch {
+	case opsType == opsapi.OpsRequestTypeUpgrade && spec.Upgrade != nil:
+		desc.addSection(getOpsUpgrade(spec.Upgrade.TargetVersion))
+	case opsType == opsapi.OpsRequestTypeHorizontalScaling && spec.HorizontalScaling != nil:
+		hs := spec.HorizontalScaling
+		var values []opsComponentValue
+		values = appendInt32Value(values, "ReplicaSet", hs.Replicas)
+		if hs.Shard != nil {
+			values = append(values,
+				opsComponentValue{component: "Shards", value: fmt.Sprintf("%d", hs.Shard.Shards)},
+				opsComponentValue{component: "Shard Replicas", value: fmt.Sprintf("%d", hs.Shard.Replicas)},
+			)
+		}
+		if hs.ConfigServer != nil {
+			values = append(values, opsComponentValue{component: "ConfigServer", value: fmt.Sprintf("%d", hs.ConfigServer.Replicas)})
+		}
+		if hs.Mongos != nil {
+			values = append(values, opsComponentValue{component: "Mongos", value: fmt.Sprintf("%d", hs.Mongos.Replicas)})
+		}
+		desc.addSection(getOpsComponents("Horizontal Scaling", "Replicas", values))
+	case opsType == opsapi.OpsRequestTypeVerticalScaling && spec.VerticalScaling != nil:
+		vs := spec.VerticalScaling
+		var resources []opsComponentResources
+		resources = appendResources(resources, "Standalone", vs.Standalone)
+		resources = appendResources(resources, "ReplicaSet", vs.ReplicaSet)
+		resources = appendResources(resources, "Shard", vs.Shard)
+		resources = appendResources(resources, "ConfigServer", vs.ConfigServer)
+		resources = appendResources(resources, "Mongos", vs.Mongos)
+		resources = appendResources(resources, "Exporter", vs.Exporter)
+		desc.addSection(getOpsResources(resources))
+	case opsType == opsapi.OpsRequestTypeVolumeExpansion && spec.VolumeExpansion != nil:
+		ve := spec.VolumeExpansion
+		var values []opsComponentValue
+		values = appendQuantityValue(values, "Standalone", ve.Standalone)
+		values = appendQuantityValue(values, "ReplicaSet", ve.ReplicaSet)
+		values = appendQuantityValue(values, "Shard", ve.Shard)
+		values = appendQuantityValue(values, "ConfigServer", ve.ConfigServer)
+		desc.addSection(getOpsComponents("Volume Expansion", "Size", values))
+	case opsType == opsapi.OpsRequestTypeReconfigure && spec.Configuration != nil:
+		c := spec.Configuration
+		for _, cfg := range []struct {
+			component string
+			config    *opsapi.MongoDBCustomConfiguration
+		}{
+			{"Standalone", c.Standalone},
+			{"ReplicaSet", c.ReplicaSet},
+			{"Shard", c.Shard},
+			{"ConfigServer", c.ConfigServer},
+			{"Mongos", c.Mongos},
+		} {
+			if cfg.config != nil {
+				desc.addSection(getOpsConfiguration(cfg.component, cfg.config.ConfigSecret, cfg.config.InlineConfig, cfg.config.RemoveCustomConfig))
+			}
+		}
+	case opsType == opsapi.OpsRequestTypeReconfigureTLSs && spec.TLS != nil:
+		desc.addSection(getOpsTLS(spec.TLS))
+	}
+
+	if rc := spec.ReadinessCriteria; rc != nil {
+		section := &Section{Title: "Readiness Criteria"}
+		section.addField("Oplog Max Lag Seconds", "%d", rc.OplogMaxLagSeconds)
+		section.addField("Objects Count Diff Percentage", "%d", rc.ObjectsCountDiffPercentage)
+		desc.addSection(section)
+	}
+}
+
+func describeMySQLOpsRequestSpec(opsType opsapi.OpsRequestType, spec *opsapi.MySQLOpsRequestSpec, desc *Description) {
+	switch {
+	case opsType == opsapi.OpsRequestTypeUpgrade && spec.Upgrade != nil:
+		desc.addSection(getOpsUpgrade(spec.Upgrade.TargetVersion))
+	case opsType == opsapi.OpsRequestTypeHorizontalScaling && spec.HorizontalScaling != nil:
+		values := appendInt32Value(nil, "Member", spec.HorizontalScaling.Member)
+		desc.addSection(getOpsComponents("Horizontal Scaling", "Replicas", values))
+	case opsType == opsapi.OpsRequestTypeVerticalScaling && spec.VerticalScaling != nil:
+		resources := appendResources(nil, "MySQL", spec.VerticalScaling.MySQL)
+		resources = appendResources(resources, "Exporter", spec.VerticalScaling.Exporter)
+		desc.addSection(getOpsResources(resources))
+	case opsType == opsapi.OpsRequestTypeVolumeExpansion && spec.VolumeExpansion != nil:
+		values := appendQuantityValue(nil, "MySQL", spec.VolumeExpansion.MySQL)
+		desc.addSection(getOpsComponents("Volume Expansion", "Size", values))
+	case opsType == opsapi.OpsRequestTypeReconfigure && spec.Configuration != nil:
+		c := spec.Configuration
+		desc.addSection(getOpsConfiguration("MySQL", c.ConfigSecret, c.InlineConfig, c.RemoveCustomConfig))
+	case opsType == opsapi.OpsRequestTypeReconfigureTLSs && spec.TLS != nil:
+		section := getOpsTLS(&spec.TLS.TLSSpec)
+		if spec.TLS.RequireSSL != nil {
+			section.addField("RequireSSL", "%v", *spec.TLS.RequireSSL)
+		}
+		desc.addSection(section)
+	}
+
+	if spec.StatefulSetOrdinal != nil {
+		desc.addField("StatefulSet Ordinal", "%d", *spec.StatefulSetOrdinal)
+	}
+}
+
+func describePostgresOpsRequestSpec(opsType opsapi.OpsRequestType, spec *opsapi.PostgresOpsRequestSpec, desc *Description) {
+	switch {
+	case opsType == opsapi.OpsRequestTypeUpgrade && spec.Upgrade != nil:
+		desc.addSection(getOpsUpgrade(spec.Upgrade.TargetVersion))
+	case opsType == opsapi.OpsRequestTypeHorizontalScaling && spec.HorizontalScaling != nil:
+		values := appendInt32Value(nil, "Postgres", spec.HorizontalScaling.Replicas)
+		desc.addSection(getOpsComponents("Horizontal Scaling", "Replicas", values))
+	case opsType == opsapi.OpsRequestTypeVerticalScaling && spec.VerticalScaling != nil:
+		resources := appendResources(nil, "Postgres", spec.VerticalScaling.Postgres)
+		resources = appendResources(resources, "Exporter", spec.VerticalScaling.Exporter)
+		desc.addSection(getOpsResources(resources))
+	case opsType == opsapi.OpsRequestTypeVolumeExpansion && spec.VolumeExpansion != nil:
+		values := appendQuantityValue(nil, "Postgres", spec.VolumeExpansion.Postgres)
+		desc.addSection(getOpsComponents("Volume Expansion", "Size", values))
+	case opsType == opsapi.OpsRequestTypeReconfigure && spec.Configuration != nil:
+		c := spec.Configuration
+		desc.addSection(getOpsConfiguration("Postgres", c.ConfigSecret, c.InlineConfig, c.RemoveCustomConfig))
+	case opsType == opsapi.OpsRequestTypeReconfigureTLSs && spec.TLS != nil:
+		section := getOpsTLS(&spec.TLS.TLSSpec)
+		if spec.TLS.SSLMode != "" {
+			section.addField("SSL Mode", "%s", spec.TLS.SSLMode)
+		}
+		if spec.TLS.ClientAuthMode != "" {
+			section.addField("Client Auth Mode", "%s", spec.TLS.ClientAuthMode)
+		}
+		desc.addSection(section)
+	}
+}
+
+func describeRedisOpsRequestSpec(opsType opsapi.OpsRequestType, spec *opsapi.RedisOpsRequestSpec, desc *Description) {
+	switch {
+	case opsType == opsapi.OpsRequestTypeUpgrade && spec.Upgrade != nil:
+		desc.addSection(getOpsUpgrade(spec.Upgrade.TargetVersion))
+	case opsType == opsapi.OpsRequestTypeHorizontalScaling && spec.HorizontalScaling != nil:
+		var values []opsComponentValue
+		values = appendInt32Value(values, "Master", spec.HorizontalScaling.Master)
+		values = appendInt32Value(values, "Replicas Per Master", spec.HorizontalScaling.Replicas)
+		desc.addSection(getOpsComponents("Horizontal Scaling", "Replicas", values))
+	case opsType == opsapi.OpsRequestTypeVerticalScaling && spec.VerticalScaling != nil:
+		resources := appendResources(nil, "Redis", spec.VerticalScaling.Redis)
+		resources = appendResources(resources, "Exporter", spec.VerticalScaling.Exporter)
+		desc.addSection(getOpsResources(resources))
+	case opsType == opsapi.OpsRequestTypeVolumeExpansion && spec.VolumeExpansion != nil:
+		values := appendQuantityValue(nil, "Redis", spec.VolumeExpansion.Redis)
+		desc.addSection(getOpsComponents("Volume Expansion", "Size", values))
+	case opsType == opsapi.OpsRequestTypeReconfigure && spec.Configuration != nil:
+		c := spec.Configuration
+		desc.addSection(getOpsConfiguration("Redis", c.ConfigSecret, c.InlineConfig, c.RemoveCustomConfig))
+	case opsType == opsapi.OpsRequestTypeReconfigureTLSs && spec.TLS != nil:
+		desc.addSection(getOpsTLS(spec.TLS))
+	}
+}
+
+type opsComponentValue struct {
+	component string
+	value     string
+}
+
+type opsComponentResources struct {
+	component string
+	resources *core.ResourceRequirements
+}
+
+func appendInt32Value(values []opsComponentValue, component string, v *int32) []opsComponentValue {
+	if v == nil {
+		return values
+	}
+	return append(values, opsComponentValue{component: component, value: fmt.Sprintf("%d", *v)})
+}
+
+func appendQuantityValue(values []opsComponentValue, component string, q *resource.Quantity) []opsComponentValue {
+	if q == nil {
+		return values
+	}
+	return append(values, opsComponentValue{component: component, value: q.String()})
+}
+
+func appendResources(resources []opsComponentResources, component string, r *core.ResourceRequirements) []opsComponentResources {
+	if r == nil {
+		return resources
+	}
+	return append(resources, opsComponentResources{component: component, resources: r})
+}
+
+func getOpsUpgrade(targetVersion string) *Section {
+	section := &Section{Title: "Upgrade"}
+	section.addField("Target Version", "%s", targetVersion)
+	return section
+}
+
+func getOpsComponents(title, column string, values []opsComponentValue) *Section {
+	section := &Section{Title: title}
+	if len(values) == 0 {
+		return section
+	}
+	section.Table = newTable("Component", column)
+	for _, v := range values {
+		section.Table.addRow(v.component, v.value)
+	}
+	return section
+}
+
+func getOpsResources(resources []opsComponentResources) *Section {
+	section := &Section{Title: "Vertical Scaling"}
+	for _, r := range resources {
+		component := &Section{Title: r.component}
+		component.addField("Requests", "%s", formatResourceList(r.resources.Requests))
+		component.addField("Limits", "%s", formatResourceList(r.resources.Limits))
+		section.addSection(component)
+	}
+	return section
+}
+
+func getOpsConfiguration(component string, configSecret *core.LocalObjectReference, inlineConfig string, remove bool) *Section {
+	section := &Section{Title: fmt.Sprintf("Configuration (%s)", component)}
+	if configSecret != nil {
+		section.addField("Config Secret", "%s", configSecret.Name)
+	}
+	if inlineConfig != "" {
+		section.addField("Inline Config", "%s", strings.TrimSpace(inlineConfig))
+	}
+	section.addField("Remove Custom Config", "%v", remove)
+	return section
+}
+
+func getOpsTLS(tls *opsapi.TLSSpec) *Section {
+	section := &Section{Title: "Reconfigure TLS"}
+	if tls.IssuerRef != nil {
+		section.addField("Issuer", "%s", formatTypedLocalObjectReference(tls.IssuerRef))
+	}
+	section.addField("Rotate Certificates", "%v", tls.RotateCertificates)
+	section.addField("Remove", "%v", tls.Remove)
+	if len(tls.Certificates) > 0 {
+		aliases := make([]string, 0, len(tls.Certificates))
+		for _, cert := range tls.Certificates {
+			aliases = append(aliases, cert.Alias)
+		}
+		section.addField("Certificates", "%s", strings.Join(aliases, ", "))
+	}
+	return section
+}
@@ -0,0 +1,200 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
+
+	"gomodules.xyz/pointer"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/describe"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	appcat_cs "kmodules.xyz/custom-resources/client/clientset/versioned"
+	stash "stash.appscode.dev/apimachinery/client/clientset/versioned"
+)
+
+type PgBouncerDescriber struct {
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
+}
+
+func (d *PgBouncerDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
+	if err != nil {
+		return "", err
+	}
+	return RenderText(desc)
+}
+
+func (d *PgBouncerDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.PgBouncers(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.SelectorFromSet(item.OffshootSelectors())
+
+	var events []Event
+	if describerSettings.ShowEvents {
+		events, err = getEvents(d.client, item.Namespace, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d.describePgBouncer(item, selector, events)
+}
+
+func (d *PgBouncerDescriber) describePgBouncer(item *api.PgBouncer, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindPgBouncer), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+
+	desc.addSection(getConnectionPool(item.Spec.ConnectionPool))
+
+	databases, err := d.getDatabases(item)
+	if err != nil {
+		return nil, err
+	}
+	desc.addSection(databases)
+
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
+
+	secrets := make(map[string]*core.LocalObjectReference)
+	if item.Spec.UserListSecretRef != nil {
+		secrets["UserList"] = item.Spec.UserListSecretRef
+	}
+	for _, db := range item.Spec.Databases {
+		if db.AuthSecretRef != nil {
+			secrets[fmt.Sprintf("%s Auth", db.Alias)] = db.AuthSecretRef
+		}
+	}
+	desc.Secrets = getSecrets(d.client, item.Namespace, secrets)
+
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
+	desc.Monitor = item.Spec.Monitor
+
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindPgBouncer, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindPgBouncer, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	desc.Events = events
+	return desc, nil
+}
+
+func getConnectionPool(pool *api.ConnectionPoolConfig) *Section {
+	section := &Section{Title: "Connection Pool"}
+	if pool == nil {
+		return section
+	}
+
+	section.addField("Port", "%s", formatInt32Ptr(pool.Port))
+	section.addField("Pool Mode", "%s", pool.PoolMode)
+	section.addField("Max Client Connections", "%s", formatInt64Ptr(pool.MaxClientConnections))
+	section.addField("Default Pool Size", "%s", formatInt64Ptr(pool.DefaultPoolSize))
+	section.addField("Min Pool Size", "%s", formatInt64Ptr(pool.MinPoolSize))
+	section.addField("Reserve Pool Size", "%s", formatInt64Ptr(pool.ReservePoolSize))
+	section.addField("Reserve Pool Timeout Seconds", "%s", formatInt64Ptr(pool.ReservePoolTimeoutSeconds))
+	section.addField("Max DB Connections", "%s", formatInt64Ptr(pool.MaxDBConnections))
+	section.addField("Max User Connections", "%s", formatInt64Ptr(pool.MaxUserConnections))
+	section.addField("Stats Period Seconds", "%s", formatInt64Ptr(pool.StatsPeriodSeconds))
+	if len(pool.AdminUsers) > 0 {
+		section.addField("Admin Users", "%s", strings.Join(pool.AdminUsers, ", "))
+	} else {
+		section.addField("Admin Users", "%s", ValueNone)
+	}
+	section.addField("Auth Type", "%s", pool.AuthType)
+	if pool.AuthUser != "" {
+		section.addField("Auth User", "%s", pool.AuthUser)
+	}
+	if pool.IgnoreStartupParameters != "" {
+		section.addField("Ignore Startup Parameters", "%s", pool.IgnoreStartupParameters)
+	}
+	return section
+}
+
+// getDatabases lists the databases served by the PgBouncer. Each DatabaseRef
+// is resolved to its AppBinding and, when that AppBinding is managed by KubeDB,
+// to the backend Postgres so that its readiness is visible from here.
+func (d *PgBouncerDescriber) getDatabases(item *api.PgBouncer) (*Section, error) {
+	section := &Section{Title: "Databases"}
+	if len(item.Spec.Databases) == 0 {
+		return section, nil
+	}
+
+	postgresAppType := api.Postgres{}.AppBindingMeta().Type()
+
+	section.Table = newTable("Alias", "Database", "AppBinding", "Backend", "Phase", "Ready")
+	for _, db := range item.Spec.Databases {
+		namespace := db.DatabaseRef.Namespace
+		if namespace == "" {
+			namespace = item.Namespace
+		}
+		appBinding := fmt.Sprintf("%s/%s", namespace, db.DatabaseRef.Name)
+		backend, phase, ready := ValueNone, ValueNone, ValueNone
+
+		ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(namespace).Get(context.TODO(), db.DatabaseRef.Name, metav1.GetOptions{})
+		switch {
+		case kerr.IsNotFound(err):
+			appBinding += " (not found)"
+		case err != nil:
+			return nil, err
+		case ab.Spec.Type == postgresAppType:
+			pg, err := d.kubedb.Postgreses(ab.Namespace).Get(context.TODO(), ab.Name, metav1.GetOptions{})
+			if err != nil && !kerr.IsNotFound(err) {
+				return nil, err
+			}
+			backend = fmt.Sprintf("%s/%s", api.ResourceKindPostgres, ab.Name)
+			if kerr.IsNotFound(err) {
+				backend += " (not found)"
+			} else {
+				phase = string(pg.Status.Phase)
+				ready = fmt.Sprintf("%v", kmapi.IsConditionTrue(pg.Status.Conditions, api.DatabaseReady))
+			}
+		case ab.Spec.ClientConfig.Service != nil:
+			backend = fmt.Sprintf("Service/%s:%d", ab.Spec.ClientConfig.Service.Name, ab.Spec.ClientConfig.Service.Port)
+		case ab.Spec.ClientConfig.URL != nil:
+			backend = *ab.Spec.ClientConfig.URL
+		}
+
+		section.Table.addRow(db.Alias, db.DatabaseName, appBinding, backend, phase, ready)
+	}
+	return section, nil
+}
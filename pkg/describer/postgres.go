@@ -18,10 +18,8 @@ package describer
 
 import (
 	"context"
-	"io"
 
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
-	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
 
 	"gomodules.xyz/pointer"
@@ -29,6 +27,7 @@ import (
 	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/describe"
 	kmapi "kmodules.xyz/client-go/api/v1"
@@ -39,97 +38,143 @@ import (
 )
 
 type PostgresDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha2Interface
-	stash  stash.Interface
-	appcat appcat_cs.Interface
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
 }
 
 func (d *PostgresDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.Postgreses(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
 	if err != nil {
 		return "", err
 	}
+	return RenderText(desc)
+}
+
+func (d *PostgresDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.Postgreses(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
 
-	var events *core.EventList
+	var events []Event
 	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
+		events, err = getEvents(d.client, item.Namespace, item)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
 	return d.describePostgres(item, selector, events)
 }
 
-func (d *PostgresDescriber) describePostgres(item *api.Postgres, selector labels.Selector, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := describe.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", pointer.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
+func (d *PostgresDescriber) describePostgres(item *api.Postgres, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindPostgres), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	desc.setStorage(item.Spec.StorageType, item.Spec.Storage)
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+	desc.addField("Halted", "%v", item.Spec.Halted)
+	desc.addField("Termination Policy", "%v", item.Spec.TerminationPolicy)
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
 
-		w.Write(LEVEL_0, "Paused:\t%v\n", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
-		w.Write(LEVEL_0, "Halted:\t%v\n", item.Spec.Halted)
-		w.Write(LEVEL_0, "Termination Policy:\t%v\n", item.Spec.TerminationPolicy)
+	secrets := make(map[string]*core.LocalObjectReference)
+	if item.Spec.AuthSecret != nil {
+		secrets["Auth"] = item.Spec.AuthSecret
+	}
+	desc.Secrets = getSecrets(d.client, item.Namespace, secrets)
 
-		showWorkload(d.client, item.Namespace, selector, w)
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
 
-		secrets := make(map[string]*core.LocalObjectReference)
-		if item.Spec.AuthSecret != nil {
-			secrets["Auth"] = item.Spec.AuthSecret
-		}
-		showSecret(d.client, item.Namespace, secrets, w)
+	specific := map[string]labels.Selector{
+		"primary": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "primary"}),
+		"replica": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "replica"}),
+	}
+	desc.Topology = getTopology(d.client, item.Namespace, selector, specific)
+	desc.addSection(getPostgresHighAvailability(item))
 
-		specific := map[string]labels.Selector{
-			"primary": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "primary"}),
-			"replica": labels.SelectorFromSet(map[string]string{"kubedb.com/role": "replica"}),
-		}
-		showTopology(d.client, item.Namespace, selector, specific, w)
+	desc.Monitor = item.Spec.Monitor
+	desc.Init = item.Spec.Init
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
-		}
+	var err error
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindPostgres, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindPostgres, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
 
-		// Show initialization information
-		describeInitialization(item.Spec.Init, w)
+	ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return nil, err
+	}
 
-		ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
-		if err != nil && !kerr.IsNotFound(err) {
-			return err
+	// Backup information
+	if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		desc.Backup, err = getBackup(d.stash, ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Show Backup information
-		if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
-			err = showBackups(d.stash, ab, w)
-			if err != nil {
-				return err
-			}
+	if ab != nil {
+		desc.AppBinding, err = getAppBinding(ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Show AppBinding
-		if ab != nil {
-			err = showAppBinding(ab, w)
-			if err != nil {
-				return err
-			}
-		}
+	desc.Events = events
+	return desc, nil
+}
 
-		if events != nil {
-			DescribeEvents(events, w)
-		}
+// getPostgresHighAvailability returns the settings that decide how the
+// replicas follow the primary and when a failover happens.
+func getPostgresHighAvailability(item *api.Postgres) *Section {
+	section := &Section{Title: "High Availability"}
+	if item.Spec.StandbyMode != nil {
+		section.addField("Standby Mode", "%s", *item.Spec.StandbyMode)
+	} else {
+		section.addField("Standby Mode", "%s", ValueNone)
+	}
+	if item.Spec.StreamingMode != nil {
+		section.addField("Streaming Mode", "%s", *item.Spec.StreamingMode)
+	} else {
+		section.addField("Streaming Mode", "%s", ValueNone)
+	}
+	section.addField("Client Auth Mode", "%s", item.Spec.ClientAuthMode)
+	section.addField("SSL Mode", "%s", item.Spec.SSLMode)
 
-		return nil
-	})
+	le := item.Spec.LeaderElection
+	if le == nil {
+		section.addSection(&Section{Title: "Leader Election"})
+		return section
+	}
+	leaderElection := &Section{Title: "Leader Election"}
+	leaderElection.addField("Lease Duration", "%ds", le.LeaseDurationSeconds)
+	leaderElection.addField("Renew Deadline", "%ds", le.RenewDeadlineSeconds)
+	leaderElection.addField("Retry Period", "%ds", le.RetryPeriodSeconds)
+	leaderElection.addField("Maximum Lag Before Failover", "%d bytes", le.MaximumLagBeforeFailover)
+	if le.Period.Duration > 0 {
+		leaderElection.addField("Period", "%s", le.Period.Duration)
+	}
+	if le.ElectionTick > 0 {
+		leaderElection.addField("Election Tick", "%d", le.ElectionTick)
+	}
+	if le.HeartbeatTick > 0 {
+		leaderElection.addField("Heartbeat Tick", "%d", le.HeartbeatTick)
+	}
+	section.addSection(leaderElection)
+	return section
 }
@@ -18,7 +18,7 @@ package describer
 
 import (
 	"fmt"
-	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -26,42 +26,62 @@ import (
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
-	"k8s.io/apimachinery/pkg/util/sets"
 )
 
-// Pass ports=nil for all ports.
-func formatEndpoints(endpoints *core.Endpoints, ports sets.String) string {
-	if len(endpoints.Subsets) == 0 {
-		return "<none>"
+// formatEndpoints formats at most 3 endpoints, followed by the number of
+// endpoints that were left out.
+func formatEndpoints(endpoints []string) string {
+	if len(endpoints) == 0 {
+		return ValueNone
 	}
-	list := []string{}
 	max := 3
-	more := false
-	count := 0
-	for i := range endpoints.Subsets {
-		ss := &endpoints.Subsets[i]
-		for i := range ss.Ports {
-			port := &ss.Ports[i]
-			if ports == nil || ports.Has(port.Name) {
-				for i := range ss.Addresses {
-					if len(list) == max {
-						more = true
-					}
-					addr := &ss.Addresses[i]
-					if !more {
-						hostPort := net.JoinHostPort(addr.IP, strconv.Itoa(int(port.Port)))
-						list = append(list, hostPort)
-					}
-					count++
-				}
-			}
-		}
+	if len(endpoints) <= max {
+		return strings.Join(endpoints, ",")
 	}
-	ret := strings.Join(list, ",")
-	if more {
-		return fmt.Sprintf("%s + %d more...", ret, count-max)
+	return fmt.Sprintf("%s + %d more...", strings.Join(endpoints[:max], ","), len(endpoints)-max)
+}
+
+func formatInt32Ptr(v *int32) string {
+	if v == nil {
+		return ValueNone
+	}
+	return strconv.Itoa(int(*v))
+}
+
+func formatInt64Ptr(v *int64) string {
+	if v == nil {
+		return ValueNone
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// formatTypedLocalObjectReference formats a TypedLocalObjectReference as Kind/Name,
+// prefixed with the APIGroup when one is set
+func formatTypedLocalObjectReference(ref *core.TypedLocalObjectReference) string {
+	if ref.APIGroup != nil && *ref.APIGroup != "" {
+		return fmt.Sprintf("%s.%s/%s", ref.Kind, *ref.APIGroup, ref.Name)
 	}
-	return ret
+	return fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+}
+
+// formatResourceList formats a ResourceList as comma separated name=quantity
+// pairs sorted by resource name
+func formatResourceList(rl core.ResourceList) string {
+	if len(rl) == 0 {
+		return ValueNone
+	}
+	names := make([]string, 0, len(rl))
+	for name := range rl {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		q := rl[core.ResourceName(name)]
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, q.String()))
+	}
+	return strings.Join(pairs, ", ")
 }
 
 // formatEventSource formats EventSource as a comma separated string excluding Host when empty
@@ -73,6 +93,10 @@ func formatEventSource(es core.EventSource) string {
 	return strings.Join(EventSourceString, ", ")
 }
 
+// timeNow returns the time that ages and certificate expiry are computed
+// against. Tests replace it to get a reproducible output.
+var timeNow = time.Now
+
 // translateTimestamp returns the elapsed time since timestamp in
 // human-readable approximation.
 func translateTimestamp(timestamp metav1.Time) string {
@@ -80,7 +104,7 @@ func translateTimestamp(timestamp metav1.Time) string {
 		return "<unknown>"
 	}
 
-	return duration.ShortHumanDuration(time.Since(timestamp.Time))
+	return duration.ShortHumanDuration(timeNow().Sub(timestamp.Time))
 }
 
 func timeToString(t *metav1.Time) string {
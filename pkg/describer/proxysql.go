@@ -0,0 +1,180 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"context"
+	"strings"
+
+	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
+	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
+
+	"gomodules.xyz/pointer"
+	core "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/describe"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	appcat_cs "kmodules.xyz/custom-resources/client/clientset/versioned"
+	stash "stash.appscode.dev/apimachinery/client/clientset/versioned"
+)
+
+type ProxySQLDescriber struct {
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
+}
+
+func (d *ProxySQLDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
+	if err != nil {
+		return "", err
+	}
+	return RenderText(desc)
+}
+
+func (d *ProxySQLDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.ProxySQLs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.SelectorFromSet(item.OffshootSelectors())
+
+	var events []Event
+	if describerSettings.ShowEvents {
+		events, err = getEvents(d.client, item.Namespace, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return d.describeProxySQL(item, selector, events)
+}
+
+func (d *ProxySQLDescriber) describeProxySQL(item *api.ProxySQL, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindProxySQL), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	if item.Spec.Mode != nil {
+		desc.addField("Mode", "%s", *item.Spec.Mode)
+	}
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+
+	backend, err := d.getBackend(item)
+	if err != nil {
+		return nil, err
+	}
+	desc.addSection(backend)
+
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
+
+	secrets := make(map[string]*core.LocalObjectReference)
+	if item.Spec.AuthSecret != nil {
+		secrets["Auth"] = item.Spec.AuthSecret
+	}
+	if item.Spec.ConfigSecret != nil {
+		secrets["Config"] = item.Spec.ConfigSecret
+	}
+	desc.Secrets = getSecrets(d.client, item.Namespace, secrets)
+
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
+	desc.Monitor = item.Spec.Monitor
+
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindProxySQL, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindProxySQL, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	desc.Events = events
+	return desc, nil
+}
+
+// getBackend follows Backend.Ref to the database that sits behind the proxy
+// and summarizes its phase, replica count and primary pod(s).
+func (d *ProxySQLDescriber) getBackend(item *api.ProxySQL) (*Section, error) {
+	section := &Section{Title: "Backend"}
+	if item.Spec.Backend == nil || item.Spec.Backend.Ref == nil {
+		return section, nil
+	}
+	ref := item.Spec.Backend.Ref
+
+	section.addField("Ref", "%s", formatTypedLocalObjectReference(ref))
+	if item.Spec.Backend.Replicas != nil {
+		section.addField("Replicas", "%d", pointer.Int32(item.Spec.Backend.Replicas))
+	}
+
+	var (
+		phase     api.DatabasePhase
+		replicas  *int32
+		selectors map[string]string
+		err       error
+	)
+	switch ref.Kind {
+	case api.ResourceKindMySQL:
+		var db *api.MySQL
+		if db, err = d.kubedb.MySQLs(item.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{}); err == nil {
+			phase, replicas, selectors = db.Status.Phase, db.Spec.Replicas, db.OffshootSelectors()
+		}
+	case api.ResourceKindMariaDB:
+		var db *api.MariaDB
+		if db, err = d.kubedb.MariaDBs(item.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{}); err == nil {
+			phase, replicas, selectors = db.Status.Phase, db.Spec.Replicas, db.OffshootSelectors()
+		}
+	case api.ResourceKindPerconaXtraDB:
+		var db *api.PerconaXtraDB
+		if db, err = d.kubedb.PerconaXtraDBs(item.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{}); err == nil {
+			phase, replicas, selectors = db.Status.Phase, db.Spec.Replicas, db.OffshootSelectors()
+		}
+	default:
+		section.addNote("Unsupported backend kind %q.", ref.Kind)
+		return section, nil
+	}
+	if kerr.IsNotFound(err) {
+		section.addNote("%s %s/%s not found.", ref.Kind, item.Namespace, ref.Name)
+		return section, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	section.addField("Status", "%s", phase)
+	section.addField("Database Replicas", "%s", formatInt32Ptr(replicas))
+
+	primaries, err := getPrimaryPods(d.client, item.Namespace, selectors)
+	if err != nil {
+		return nil, err
+	}
+	if len(primaries) == 0 {
+		section.addField("Primary", "%s", ValueNone)
+	} else {
+		section.addField("Primary", "%s", strings.Join(primaries, ", "))
+	}
+	return section, nil
+}
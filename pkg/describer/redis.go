@@ -18,10 +18,10 @@ package describer
 
 import (
 	"context"
-	"io"
+	"fmt"
+	"sort"
 
 	api "kubedb.dev/apimachinery/apis/kubedb/v1alpha2"
-	"kubedb.dev/apimachinery/client/clientset/versioned/scheme"
 	cs "kubedb.dev/apimachinery/client/clientset/versioned/typed/kubedb/v1alpha2"
 
 	"gomodules.xyz/pointer"
@@ -29,9 +29,11 @@ import (
 	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubectl/pkg/describe"
 	kmapi "kmodules.xyz/client-go/api/v1"
+	core_util "kmodules.xyz/client-go/core/v1"
 	"kmodules.xyz/client-go/discovery"
 	appcat_cs "kmodules.xyz/custom-resources/client/clientset/versioned"
 	stashV1beta1 "stash.appscode.dev/apimachinery/apis/stash/v1beta1"
@@ -39,82 +41,169 @@ import (
 )
 
 type RedisDescriber struct {
-	client kubernetes.Interface
-	kubedb cs.KubedbV1alpha2Interface
-	stash  stash.Interface
-	appcat appcat_cs.Interface
+	client  kubernetes.Interface
+	kubedb  cs.KubedbV1alpha2Interface
+	stash   stash.Interface
+	appcat  appcat_cs.Interface
+	dynamic dynamic.Interface
+	options Options
 }
 
 func (d *RedisDescriber) Describe(namespace, name string, describerSettings describe.DescriberSettings) (string, error) {
-	item, err := d.kubedb.Redises(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	desc, err := d.DescribeObject(namespace, name, describerSettings)
 	if err != nil {
 		return "", err
 	}
+	return RenderText(desc)
+}
+
+func (d *RedisDescriber) DescribeObject(namespace, name string, describerSettings describe.DescriberSettings) (*Description, error) {
+	item, err := d.kubedb.Redises(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
 
 	selector := labels.SelectorFromSet(item.OffshootSelectors())
 
-	var events *core.EventList
+	var events []Event
 	if describerSettings.ShowEvents {
-		events, err = d.client.CoreV1().Events(item.Namespace).Search(scheme.Scheme, item)
+		events, err = getEvents(d.client, item.Namespace, item)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
 	return d.describeRedis(item, selector, events)
 }
 
-func (d *RedisDescriber) describeRedis(item *api.Redis, selector labels.Selector, events *core.EventList) (string, error) {
-	return tabbedString(func(out io.Writer) error {
-		w := describe.NewPrefixWriter(out)
-		w.Write(LEVEL_0, "Name:\t%s\n", item.Name)
-		w.Write(LEVEL_0, "Namespace:\t%s\n", item.Namespace)
-		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&item.CreationTimestamp))
-		printLabelsMultiline(LEVEL_0, w, "Labels", item.Labels)
-		printAnnotationsMultiline(LEVEL_0, w, "Annotations", item.Annotations)
-
-		if item.Spec.Replicas != nil {
-			w.Write(LEVEL_0, "Replicas:\t%d  total\n", pointer.Int32(item.Spec.Replicas))
-		}
-		w.Write(LEVEL_0, "Status:\t%s\n", string(item.Status.Phase))
+func (d *RedisDescriber) describeRedis(item *api.Redis, selector labels.Selector, events []Event) (*Description, error) {
+	desc := newDescription(api.SchemeGroupVersion.WithKind(api.ResourceKindRedis), item.ObjectMeta)
+	if item.Spec.Replicas != nil {
+		desc.addField("Replicas", "%d  total", pointer.Int32(item.Spec.Replicas))
+	}
+	desc.addField("Status", "%s", item.Status.Phase)
+	desc.setStorage(item.Spec.StorageType, item.Spec.Storage)
+	desc.addField("Paused", "%v", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
+	desc.addField("Halted", "%v", item.Spec.Halted)
+	desc.addField("Termination Policy", "%v", item.Spec.TerminationPolicy)
 
-		describeStorage(item.Spec.StorageType, item.Spec.Storage, w)
+	desc.Workloads, desc.Services = getWorkloads(d.client, item.Namespace, selector)
 
-		w.Write(LEVEL_0, "Paused:\t%v\n", kmapi.IsConditionTrue(item.Status.Conditions, api.DatabasePaused))
-		w.Write(LEVEL_0, "Halted:\t%v\n", item.Spec.Halted)
-		w.Write(LEVEL_0, "Termination Policy:\t%v\n", item.Spec.TerminationPolicy)
+	desc.TLS = item.Spec.TLS
+	desc.Certificates = GetCertificates(d.client, item.Namespace, item.Name, item.Spec.TLS, d.options.CertificateExpiryWindow)
 
-		showWorkload(d.client, item.Namespace, selector, w)
+	if item.Spec.Mode == api.RedisModeCluster && item.Spec.Cluster != nil {
+		desc.addSection(getRedisCluster(d.client, item, selector))
+	}
 
-		if item.Spec.Monitor != nil {
-			describeMonitor(item.Spec.Monitor, w)
+	desc.Monitor = item.Spec.Monitor
+
+	var err error
+	desc.OpsRequests, err = getOpsRequests(d.dynamic, api.ResourceKindRedis, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	desc.Autoscalers, err = getAutoscalers(d.dynamic, api.ResourceKindRedis, item.Namespace, item.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return nil, err
+	}
+
+	// Backup information
+	if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
+		desc.Backup, err = getBackup(d.stash, ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		ab, err := d.appcat.AppcatalogV1alpha1().AppBindings(item.Namespace).Get(context.TODO(), item.Name, metav1.GetOptions{})
-		if err != nil && !kerr.IsNotFound(err) {
-			return err
+	if ab != nil {
+		desc.AppBinding, err = getAppBinding(ab)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Show Backup information
-		if discovery.ExistsGroupKind(d.client.Discovery(), stashV1beta1.SchemeGroupVersion.Group, stashV1beta1.ResourceKindBackupBlueprint) {
-			err = showBackups(d.stash, ab, w)
-			if err != nil {
-				return err
+	desc.Events = events
+	return desc, nil
+}
+
+const (
+	redisPodMaster  = "master"
+	redisPodReplica = "replica"
+)
+
+// getRedisCluster maps each shard StatefulSet of a Redis cluster to its master
+// and replica pods. A pod is taken as master if it is labeled so, otherwise
+// the first pod of a shard is, as the operator creates the cluster that way.
+// Shards with fewer ready replicas than configured are flagged.
+func getRedisCluster(client kubernetes.Interface, item *api.Redis, selector labels.Selector) *Section {
+	masters := pointer.Int32(item.Spec.Cluster.Master)
+	replicas := pointer.Int32(item.Spec.Cluster.Replicas)
+
+	section := &Section{Title: "Cluster"}
+	section.addField("Masters", "%d", masters)
+	section.addField("Replicas Per Master", "%d", replicas)
+
+	pods, err := client.CoreV1().Pods(item.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return section
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	for i := 0; i < int(masters); i++ {
+		stsName := item.StatefulSetNameWithShard(i)
+		var shardPods []*core.Pod
+		for j := range pods.Items {
+			if owner := metav1.GetControllerOf(&pods.Items[j]); owner != nil && owner.Kind == "StatefulSet" && owner.Name == stsName {
+				shardPods = append(shardPods, &pods.Items[j])
 			}
 		}
 
-		// Show AppBinding
-		if ab != nil {
-			err = showAppBinding(ab, w)
-			if err != nil {
-				return err
-			}
+		shard := &Section{Title: fmt.Sprintf("Shard %d", i)}
+		shard.addField("StatefulSet", "%s", stsName)
+		shard.Table = newTable("Member", "Role", "StartTime", "Phase", "Ready")
+
+		labeled := false
+		for _, pod := range shardPods {
+			labeled = labeled || pod.Labels[api.LabelRole] == redisPodMaster
 		}
 
-		if events != nil {
-			DescribeEvents(events, w)
+		var master string
+		var ready int32
+		for _, pod := range shardPods {
+			isMaster := pod.Name == fmt.Sprintf("%s-0", stsName)
+			if labeled {
+				isMaster = pod.Labels[api.LabelRole] == redisPodMaster
+			}
+
+			role := redisPodReplica
+			if isMaster {
+				role = redisPodMaster
+				master = pod.Name
+			} else if core_util.IsPodReady(pod) {
+				ready++
+			}
+			shard.Table.addRow(pod.Name, role, timeToString(pod.Status.StartTime), pod.Status.Phase, core_util.IsPodReady(pod))
 		}
 
-		return nil
-	})
+		if len(shardPods) == 0 {
+			shard.addNote("No pod found.")
+		} else if master == "" {
+			shard.addNote("No master found.")
+		}
+		if ready < replicas {
+			shard.addNote("Under-replicated: %d of %d replicas ready.", ready, replicas)
+		}
+		section.addSection(shard)
+	}
+	return section
 }
@@ -0,0 +1,141 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"io"
+	"strings"
+
+	"k8s.io/kubectl/pkg/describe"
+)
+
+// RenderText renders a Description in the tab-aligned text format of
+// `kubectl describe`.
+func RenderText(desc *Description) (string, error) {
+	return tabbedString(func(out io.Writer) error {
+		w := describe.NewPrefixWriter(out)
+		w.Write(LEVEL_0, "Name:\t%s\n", desc.Object.Name)
+		w.Write(LEVEL_0, "Namespace:\t%s\n", desc.Object.Namespace)
+		w.Write(LEVEL_0, "CreationTimestamp:\t%s\n", timeToString(&desc.Object.CreationTimestamp))
+		printLabelsMultiline(LEVEL_0, w, "Labels", desc.Object.Labels)
+		printAnnotationsMultiline(LEVEL_0, w, "Annotations", desc.Object.Annotations)
+		if desc.Storage != nil {
+			describeFields(LEVEL_0, desc.Fields[:desc.storageAt], w)
+			describeStorage(desc.Storage.Type, desc.Storage.Volume, w)
+			describeFields(LEVEL_0, desc.Fields[desc.storageAt:], w)
+		} else {
+			describeFields(LEVEL_0, desc.Fields, w)
+		}
+
+		for i := range desc.Workloads {
+			describeWorkload(&desc.Workloads[i], w)
+		}
+		for i := range desc.Services {
+			describeService(&desc.Services[i], w)
+		}
+		for i := range desc.Secrets {
+			describeSecret(&desc.Secrets[i], w)
+		}
+
+		describeTLS(desc.Object.Name, desc.TLS, w)
+		if len(desc.Certificates) > 0 {
+			describeCertificates(desc.Certificates, w)
+		}
+
+		if desc.Topology != nil {
+			describeTopology(desc.Topology, w)
+		}
+
+		for i := range desc.Sections {
+			w.Write(LEVEL_0, "\n")
+			describeSection(LEVEL_0, &desc.Sections[i], w)
+		}
+
+		describeMonitor(desc.Monitor, w)
+		describeInitialization(desc.Init, w)
+
+		if desc.OpsRequests != nil {
+			describeOpsRequestRefs(desc.OpsRequests, w)
+		}
+		if desc.Autoscalers != nil {
+			describeAutoscalerRefs(desc.Autoscalers, w)
+		}
+		if desc.Backup != nil {
+			describeBackup(desc.Backup, w)
+		}
+		if desc.AppBinding != nil {
+			describeAppBinding(desc.AppBinding, w)
+		}
+		if desc.Events != nil {
+			DescribeEvents(desc.Events, w)
+		}
+
+		return nil
+	})
+}
+
+// describeFields prints one field per line. The lines of a multi-line value
+// are aligned below the first one.
+func describeFields(level int, fields []Field, w describe.PrefixWriter) {
+	for _, f := range fields {
+		lines := strings.Split(f.Value, "\n")
+		w.Write(level, "%s:\t%s\n", f.Name, lines[0])
+		for _, line := range lines[1:] {
+			w.Write(level, "\t%s\n", line)
+		}
+	}
+}
+
+// describeSection prints the fields, the table, the nested sections and the
+// notes of a section in that order.
+func describeSection(level int, s *Section, w describe.PrefixWriter) {
+	if s.isEmpty() {
+		w.Write(level, "%s:\t%s\n", s.Title, ValueNone)
+		return
+	}
+
+	w.Write(level, "%s:\n", s.Title)
+	describeFields(level+1, s.Fields, w)
+	if s.Table != nil && len(s.Table.Rows) > 0 {
+		describeTable(level+1, s.Table, w)
+	}
+	for i := range s.Sections {
+		describeSection(level+1, &s.Sections[i], w)
+	}
+	for _, note := range s.Notes {
+		w.Write(level+1, "%s\n", note)
+	}
+}
+
+func (s *Section) isEmpty() bool {
+	return len(s.Fields) == 0 &&
+		(s.Table == nil || len(s.Table.Rows) == 0) &&
+		len(s.Sections) == 0 &&
+		len(s.Notes) == 0
+}
+
+func describeTable(level int, t *Table, w describe.PrefixWriter) {
+	dashes := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		dashes[i] = strings.Repeat("-", len(c))
+	}
+	w.Write(level, "%s\n", strings.Join(t.Columns, "\t"))
+	w.Write(level, "%s\n", strings.Join(dashes, "\t"))
+	for _, row := range t.Rows {
+		w.Write(level, "%s\n", strings.Join(row, "\t"))
+	}
+}
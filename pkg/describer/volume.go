@@ -0,0 +1,446 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/kubectl/pkg/describe"
+)
+
+// describeVolume prints a volume source with its properties nested one level
+// below the "Volume:" line. Optional fields that are not set are printed as
+// <none>.
+func describeVolume(level int, volume core.VolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Volume:\n")
+	level++
+	switch {
+	case volume.HostPath != nil:
+		printHostPathVolumeSource(level, volume.HostPath, w)
+	case volume.EmptyDir != nil:
+		printEmptyDirVolumeSource(level, volume.EmptyDir, w)
+	case volume.GCEPersistentDisk != nil:
+		printGCEPersistentDiskVolumeSource(level, volume.GCEPersistentDisk, w)
+	case volume.AWSElasticBlockStore != nil:
+		printAWSElasticBlockStoreVolumeSource(level, volume.AWSElasticBlockStore, w)
+	case volume.GitRepo != nil:
+		printGitRepoVolumeSource(level, volume.GitRepo, w)
+	case volume.Secret != nil:
+		printSecretVolumeSource(level, volume.Secret, w)
+	case volume.ConfigMap != nil:
+		printConfigMapVolumeSource(level, volume.ConfigMap, w)
+	case volume.NFS != nil:
+		printNFSVolumeSource(level, volume.NFS, w)
+	case volume.ISCSI != nil:
+		printISCSIVolumeSource(level, volume.ISCSI, w)
+	case volume.Glusterfs != nil:
+		printGlusterfsVolumeSource(level, volume.Glusterfs, w)
+	case volume.PersistentVolumeClaim != nil:
+		printPersistentVolumeClaimVolumeSource(level, volume.PersistentVolumeClaim, w)
+	case volume.RBD != nil:
+		printRBDVolumeSource(level, volume.RBD, w)
+	case volume.Quobyte != nil:
+		printQuobyteVolumeSource(level, volume.Quobyte, w)
+	case volume.DownwardAPI != nil:
+		printDownwardAPIVolumeSource(level, volume.DownwardAPI, w)
+	case volume.AzureDisk != nil:
+		printAzureDiskVolumeSource(level, volume.AzureDisk, w)
+	case volume.VsphereVolume != nil:
+		printVsphereVolumeSource(level, volume.VsphereVolume, w)
+	case volume.Cinder != nil:
+		printCinderVolumeSource(level, volume.Cinder, w)
+	case volume.PhotonPersistentDisk != nil:
+		printPhotonPersistentDiskVolumeSource(level, volume.PhotonPersistentDisk, w)
+	case volume.PortworxVolume != nil:
+		printPortworxVolumeSource(level, volume.PortworxVolume, w)
+	case volume.ScaleIO != nil:
+		printScaleIOVolumeSource(level, volume.ScaleIO, w)
+	case volume.CephFS != nil:
+		printCephFSVolumeSource(level, volume.CephFS, w)
+	case volume.StorageOS != nil:
+		printStorageOSVolumeSource(level, volume.StorageOS, w)
+	case volume.FC != nil:
+		printFCVolumeSource(level, volume.FC, w)
+	case volume.AzureFile != nil:
+		printAzureFileVolumeSource(level, volume.AzureFile, w)
+	case volume.FlexVolume != nil:
+		printFlexVolumeSource(level, volume.FlexVolume, w)
+	case volume.Flocker != nil:
+		printFlockerVolumeSource(level, volume.Flocker, w)
+	case volume.Projected != nil:
+		printProjectedVolumeSource(level, volume.Projected, w)
+	case volume.CSI != nil:
+		printCSIVolumeSource(level, volume.CSI, w)
+	case volume.Ephemeral != nil:
+		printEphemeralVolumeSource(level, volume.Ephemeral, w)
+	default:
+		w.Write(level, "<unknown>\n")
+	}
+}
+
+func printHostPathVolumeSource(level int, hostPath *core.HostPathVolumeSource, w describe.PrefixWriter) {
+	hostPathType := ValueNone
+	if hostPath.Type != nil {
+		hostPathType = string(*hostPath.Type)
+	}
+	w.Write(level, "Type:\tHostPath (bare host directory volume)\n")
+	w.Write(level, "Path:\t%v\n", hostPath.Path)
+	w.Write(level, "HostPathType:\t%v\n", hostPathType)
+}
+
+func printEmptyDirVolumeSource(level int, emptyDir *core.EmptyDirVolumeSource, w describe.PrefixWriter) {
+	sizeLimit := ValueNone
+	if emptyDir.SizeLimit != nil {
+		sizeLimit = emptyDir.SizeLimit.String()
+	}
+	w.Write(level, "Type:\tEmptyDir (a temporary directory that shares a pod's lifetime)\n")
+	w.Write(level, "Medium:\t%v\n", emptyDir.Medium)
+	w.Write(level, "SizeLimit:\t%v\n", sizeLimit)
+}
+
+func printGCEPersistentDiskVolumeSource(level int, gce *core.GCEPersistentDiskVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tGCEPersistentDisk (a Persistent Disk resource in Google Compute Engine)\n")
+	w.Write(level, "PDName:\t%v\n", gce.PDName)
+	w.Write(level, "FSType:\t%v\n", gce.FSType)
+	w.Write(level, "Partition:\t%v\n", gce.Partition)
+	w.Write(level, "ReadOnly:\t%v\n", gce.ReadOnly)
+}
+
+func printAWSElasticBlockStoreVolumeSource(level int, aws *core.AWSElasticBlockStoreVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tAWSElasticBlockStore (a Persistent Disk resource in AWS)\n")
+	w.Write(level, "VolumeID:\t%v\n", aws.VolumeID)
+	w.Write(level, "FSType:\t%v\n", aws.FSType)
+	w.Write(level, "Partition:\t%v\n", aws.Partition)
+	w.Write(level, "ReadOnly:\t%v\n", aws.ReadOnly)
+}
+
+func printGitRepoVolumeSource(level int, git *core.GitRepoVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tGitRepo (a volume that is pulled from git when the pod is created)\n")
+	w.Write(level, "Repository:\t%v\n", git.Repository)
+	w.Write(level, "Revision:\t%v\n", git.Revision)
+}
+
+func printSecretVolumeSource(level int, secret *core.SecretVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tSecret (a volume populated by a Secret)\n")
+	w.Write(level, "SecretName:\t%v\n", secret.SecretName)
+	w.Write(level, "Optional:\t%v\n", boolValue(secret.Optional))
+}
+
+func printConfigMapVolumeSource(level int, configMap *core.ConfigMapVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tConfigMap (a volume populated by a ConfigMap)\n")
+	w.Write(level, "Name:\t%v\n", configMap.Name)
+	w.Write(level, "Optional:\t%v\n", boolValue(configMap.Optional))
+}
+
+func printNFSVolumeSource(level int, nfs *core.NFSVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tNFS (an NFS mount that lasts the lifetime of a pod)\n")
+	w.Write(level, "Server:\t%v\n", nfs.Server)
+	w.Write(level, "Path:\t%v\n", nfs.Path)
+	w.Write(level, "ReadOnly:\t%v\n", nfs.ReadOnly)
+}
+
+func printQuobyteVolumeSource(level int, quobyte *core.QuobyteVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tQuobyte (a Quobyte mount on the host that shares a pod's lifetime)\n")
+	w.Write(level, "Registry:\t%v\n", quobyte.Registry)
+	w.Write(level, "Volume:\t%v\n", quobyte.Volume)
+	w.Write(level, "ReadOnly:\t%v\n", quobyte.ReadOnly)
+}
+
+func printPortworxVolumeSource(level int, pwxVolume *core.PortworxVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tPortworxVolume (a Portworx Volume resource)\n")
+	w.Write(level, "VolumeID:\t%v\n", pwxVolume.VolumeID)
+	w.Write(level, "FSType:\t%v\n", pwxVolume.FSType)
+	w.Write(level, "ReadOnly:\t%v\n", pwxVolume.ReadOnly)
+}
+
+func printISCSIVolumeSource(level int, iscsi *core.ISCSIVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tISCSI (an ISCSI Disk resource that is attached to a kubelet's host machine and then exposed to the pod)\n")
+	w.Write(level, "TargetPortal:\t%v\n", iscsi.TargetPortal)
+	w.Write(level, "IQN:\t%v\n", iscsi.IQN)
+	w.Write(level, "Lun:\t%v\n", iscsi.Lun)
+	w.Write(level, "ISCSIInterface:\t%v\n", iscsi.ISCSIInterface)
+	w.Write(level, "FSType:\t%v\n", iscsi.FSType)
+	w.Write(level, "ReadOnly:\t%v\n", iscsi.ReadOnly)
+	w.Write(level, "Portals:\t%v\n", stringsOrNone(iscsi.Portals))
+	w.Write(level, "DiscoveryCHAPAuth:\t%v\n", iscsi.DiscoveryCHAPAuth)
+	w.Write(level, "SessionCHAPAuth:\t%v\n", iscsi.SessionCHAPAuth)
+	w.Write(level, "SecretRef:\t%v\n", secretRefName(iscsi.SecretRef))
+	w.Write(level, "InitiatorName:\t%v\n", stringValue(iscsi.InitiatorName))
+}
+
+func printGlusterfsVolumeSource(level int, glusterfs *core.GlusterfsVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tGlusterfs (a Glusterfs mount on the host that shares a pod's lifetime)\n")
+	w.Write(level, "EndpointsName:\t%v\n", glusterfs.EndpointsName)
+	w.Write(level, "Path:\t%v\n", glusterfs.Path)
+	w.Write(level, "ReadOnly:\t%v\n", glusterfs.ReadOnly)
+}
+
+func printPersistentVolumeClaimVolumeSource(level int, claim *core.PersistentVolumeClaimVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tPersistentVolumeClaim (a reference to a PersistentVolumeClaim in the same namespace)\n")
+	w.Write(level, "ClaimName:\t%v\n", claim.ClaimName)
+	w.Write(level, "ReadOnly:\t%v\n", claim.ReadOnly)
+}
+
+func printRBDVolumeSource(level int, rbd *core.RBDVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tRBD (a Rados Block Device mount on the host that shares a pod's lifetime)\n")
+	w.Write(level, "CephMonitors:\t%v\n", stringsOrNone(rbd.CephMonitors))
+	w.Write(level, "RBDImage:\t%v\n", rbd.RBDImage)
+	w.Write(level, "FSType:\t%v\n", rbd.FSType)
+	w.Write(level, "RBDPool:\t%v\n", rbd.RBDPool)
+	w.Write(level, "RadosUser:\t%v\n", rbd.RadosUser)
+	w.Write(level, "Keyring:\t%v\n", rbd.Keyring)
+	w.Write(level, "SecretRef:\t%v\n", secretRefName(rbd.SecretRef))
+	w.Write(level, "ReadOnly:\t%v\n", rbd.ReadOnly)
+}
+
+func printDownwardAPIVolumeSource(level int, d *core.DownwardAPIVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tDownwardAPI (a volume populated by information about the pod)\n")
+	printDownwardAPIItems(level, d.Items, w)
+}
+
+func printDownwardAPIItems(level int, items []core.DownwardAPIVolumeFile, w describe.PrefixWriter) {
+	if len(items) == 0 {
+		w.Write(level, "Items:\t%s\n", ValueNone)
+		return
+	}
+	w.Write(level, "Items:\n")
+	for _, mapping := range items {
+		if mapping.FieldRef != nil {
+			w.Write(level+1, "%v -> %v\n", mapping.FieldRef.FieldPath, mapping.Path)
+		}
+		if mapping.ResourceFieldRef != nil {
+			w.Write(level+1, "%v -> %v\n", mapping.ResourceFieldRef.Resource, mapping.Path)
+		}
+	}
+}
+
+func printAzureDiskVolumeSource(level int, d *core.AzureDiskVolumeSource, w describe.PrefixWriter) {
+	kind := ValueNone
+	if d.Kind != nil {
+		kind = string(*d.Kind)
+	}
+	cachingMode := ValueNone
+	if d.CachingMode != nil {
+		cachingMode = string(*d.CachingMode)
+	}
+	w.Write(level, "Type:\tAzureDisk (an Azure Data Disk mount on the host and bind mount to the pod)\n")
+	w.Write(level, "DiskName:\t%v\n", d.DiskName)
+	w.Write(level, "DiskURI:\t%v\n", d.DataDiskURI)
+	w.Write(level, "Kind:\t%v\n", kind)
+	w.Write(level, "FSType:\t%v\n", stringValue(d.FSType))
+	w.Write(level, "CachingMode:\t%v\n", cachingMode)
+	w.Write(level, "ReadOnly:\t%v\n", boolValue(d.ReadOnly))
+}
+
+func printVsphereVolumeSource(level int, vsphere *core.VsphereVirtualDiskVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tvSphereVolume (a Persistent Disk resource in vSphere)\n")
+	w.Write(level, "VolumePath:\t%v\n", vsphere.VolumePath)
+	w.Write(level, "FSType:\t%v\n", vsphere.FSType)
+	w.Write(level, "StoragePolicyName:\t%v\n", vsphere.StoragePolicyName)
+}
+
+func printPhotonPersistentDiskVolumeSource(level int, photon *core.PhotonPersistentDiskVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tPhotonPersistentDisk (a Persistent Disk resource in photon platform)\n")
+	w.Write(level, "PdID:\t%v\n", photon.PdID)
+	w.Write(level, "FSType:\t%v\n", photon.FSType)
+}
+
+func printCinderVolumeSource(level int, cinder *core.CinderVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tCinder (a Persistent Disk resource in OpenStack)\n")
+	w.Write(level, "VolumeID:\t%v\n", cinder.VolumeID)
+	w.Write(level, "FSType:\t%v\n", cinder.FSType)
+	w.Write(level, "ReadOnly:\t%v\n", cinder.ReadOnly)
+	w.Write(level, "SecretRef:\t%v\n", secretRefName(cinder.SecretRef))
+}
+
+func printScaleIOVolumeSource(level int, sio *core.ScaleIOVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tScaleIO (a persistent volume backed by a block device in ScaleIO)\n")
+	w.Write(level, "Gateway:\t%v\n", sio.Gateway)
+	w.Write(level, "System:\t%v\n", sio.System)
+	w.Write(level, "Protection Domain:\t%v\n", sio.ProtectionDomain)
+	w.Write(level, "Storage Pool:\t%v\n", sio.StoragePool)
+	w.Write(level, "Storage Mode:\t%v\n", sio.StorageMode)
+	w.Write(level, "VolumeName:\t%v\n", sio.VolumeName)
+	w.Write(level, "FSType:\t%v\n", sio.FSType)
+	w.Write(level, "SecretRef:\t%v\n", secretRefName(sio.SecretRef))
+	w.Write(level, "ReadOnly:\t%v\n", sio.ReadOnly)
+}
+
+func printCephFSVolumeSource(level int, cephfs *core.CephFSVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tCephFS (a CephFS mount on the host that shares a pod's lifetime)\n")
+	w.Write(level, "Monitors:\t%v\n", stringsOrNone(cephfs.Monitors))
+	w.Write(level, "Path:\t%v\n", cephfs.Path)
+	w.Write(level, "User:\t%v\n", cephfs.User)
+	w.Write(level, "SecretFile:\t%v\n", cephfs.SecretFile)
+	w.Write(level, "SecretRef:\t%v\n", secretRefName(cephfs.SecretRef))
+	w.Write(level, "ReadOnly:\t%v\n", cephfs.ReadOnly)
+}
+
+func printStorageOSVolumeSource(level int, storageos *core.StorageOSVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tStorageOS (a StorageOS Persistent Disk resource)\n")
+	w.Write(level, "VolumeName:\t%v\n", storageos.VolumeName)
+	w.Write(level, "VolumeNamespace:\t%v\n", storageos.VolumeNamespace)
+	w.Write(level, "FSType:\t%v\n", storageos.FSType)
+	w.Write(level, "SecretRef:\t%v\n", secretRefName(storageos.SecretRef))
+	w.Write(level, "ReadOnly:\t%v\n", storageos.ReadOnly)
+}
+
+func printFCVolumeSource(level int, fc *core.FCVolumeSource, w describe.PrefixWriter) {
+	lun := ValueNone
+	if fc.Lun != nil {
+		lun = strconv.Itoa(int(*fc.Lun))
+	}
+	w.Write(level, "Type:\tFC (a Fibre Channel disk)\n")
+	w.Write(level, "TargetWWNs:\t%v\n", stringsOrNone(fc.TargetWWNs))
+	w.Write(level, "LUN:\t%v\n", lun)
+	w.Write(level, "WWIDs:\t%v\n", stringsOrNone(fc.WWIDs))
+	w.Write(level, "FSType:\t%v\n", fc.FSType)
+	w.Write(level, "ReadOnly:\t%v\n", fc.ReadOnly)
+}
+
+func printAzureFileVolumeSource(level int, azureFile *core.AzureFileVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tAzureFile (an Azure File Service mount on the host and bind mount to the pod)\n")
+	w.Write(level, "SecretName:\t%v\n", azureFile.SecretName)
+	w.Write(level, "ShareName:\t%v\n", azureFile.ShareName)
+	w.Write(level, "ReadOnly:\t%v\n", azureFile.ReadOnly)
+}
+
+func printFlexVolumeSource(level int, flex *core.FlexVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tFlexVolume (a generic volume resource that is provisioned/attached using an exec based plugin)\n")
+	w.Write(level, "Driver:\t%v\n", flex.Driver)
+	w.Write(level, "FSType:\t%v\n", flex.FSType)
+	w.Write(level, "SecretRef:\t%v\n", secretRefName(flex.SecretRef))
+	w.Write(level, "ReadOnly:\t%v\n", flex.ReadOnly)
+	w.Write(level, "Options:\t%v\n", mapOrNone(flex.Options))
+}
+
+func printFlockerVolumeSource(level int, flocker *core.FlockerVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tFlocker (a Flocker volume mounted by the Flocker agent)\n")
+	w.Write(level, "DatasetName:\t%v\n", flocker.DatasetName)
+	w.Write(level, "DatasetUUID:\t%v\n", flocker.DatasetUUID)
+}
+
+func printProjectedVolumeSource(level int, projected *core.ProjectedVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tProjected (a volume that contains injected data from multiple sources)\n")
+	if len(projected.Sources) == 0 {
+		w.Write(level, "Sources:\t%s\n", ValueNone)
+		return
+	}
+	w.Write(level, "Sources:\n")
+	for _, source := range projected.Sources {
+		switch {
+		case source.Secret != nil:
+			w.Write(level+1, "SecretName:\t%v\n", source.Secret.Name)
+			w.Write(level+1, "SecretOptional:\t%v\n", boolValue(source.Secret.Optional))
+		case source.ConfigMap != nil:
+			w.Write(level+1, "ConfigMapName:\t%v\n", source.ConfigMap.Name)
+			w.Write(level+1, "ConfigMapOptional:\t%v\n", boolValue(source.ConfigMap.Optional))
+		case source.DownwardAPI != nil:
+			w.Write(level+1, "DownwardAPI:\ttrue\n")
+			printDownwardAPIItems(level+1, source.DownwardAPI.Items, w)
+		case source.ServiceAccountToken != nil:
+			expiration := ValueNone
+			if source.ServiceAccountToken.ExpirationSeconds != nil {
+				expiration = strconv.FormatInt(*source.ServiceAccountToken.ExpirationSeconds, 10)
+			}
+			audience := source.ServiceAccountToken.Audience
+			if audience == "" {
+				audience = ValueNone
+			}
+			w.Write(level+1, "TokenExpirationSeconds:\t%v\n", expiration)
+			w.Write(level+1, "TokenAudience:\t%v\n", audience)
+			w.Write(level+1, "TokenPath:\t%v\n", source.ServiceAccountToken.Path)
+		default:
+			w.Write(level+1, "<unknown>\n")
+		}
+	}
+}
+
+func printCSIVolumeSource(level int, csi *core.CSIVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tCSI (a Container Storage Interface (CSI) volume source)\n")
+	w.Write(level, "Driver:\t%v\n", csi.Driver)
+	w.Write(level, "FSType:\t%v\n", stringValue(csi.FSType))
+	w.Write(level, "ReadOnly:\t%v\n", boolValue(csi.ReadOnly))
+	w.Write(level, "NodePublishSecretRef:\t%v\n", secretRefName(csi.NodePublishSecretRef))
+	w.Write(level, "VolumeAttributes:\t%v\n", mapOrNone(csi.VolumeAttributes))
+}
+
+func printEphemeralVolumeSource(level int, ephemeral *core.EphemeralVolumeSource, w describe.PrefixWriter) {
+	w.Write(level, "Type:\tEphemeralVolume (an inline specification for a volume that gets created and deleted with the pod)\n")
+	if ephemeral.VolumeClaimTemplate == nil {
+		w.Write(level, "VolumeClaimTemplate:\t%s\n", ValueNone)
+		return
+	}
+	spec := ephemeral.VolumeClaimTemplate.Spec
+	w.Write(level, "StorageClass:\t%v\n", stringValue(spec.StorageClassName))
+	if val, ok := spec.Resources.Requests[core.ResourceStorage]; ok {
+		w.Write(level, "Capacity:\t%v\n", val.String())
+	} else {
+		w.Write(level, "Capacity:\t%v\n", ValueNone)
+	}
+	accessModes := getAccessModesAsString(spec.AccessModes)
+	if accessModes == "" {
+		accessModes = ValueNone
+	}
+	w.Write(level, "Access Modes:\t%v\n", accessModes)
+	volumeMode := ValueNone
+	if spec.VolumeMode != nil {
+		volumeMode = string(*spec.VolumeMode)
+	}
+	w.Write(level, "VolumeMode:\t%v\n", volumeMode)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ValueNone
+	}
+	return *s
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
+
+func secretRefName(ref *core.LocalObjectReference) string {
+	if ref == nil || ref.Name == "" {
+		return ValueNone
+	}
+	return ref.Name
+}
+
+func stringsOrNone(values []string) string {
+	if len(values) == 0 {
+		return ValueNone
+	}
+	return strings.Join(values, ", ")
+}
+
+// mapOrNone formats a map as comma separated key=value pairs sorted by key.
+func mapOrNone(m map[string]string) string {
+	if len(m) == 0 {
+		return ValueNone
+	}
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,297 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubectl/pkg/describe"
+)
+
+func TestDescribeVolume(t *testing.T) {
+	var (
+		readOnly          = true
+		fsType            = "ext4"
+		lun         int32 = 3
+		expiration  int64 = 3600
+		storageName       = "standard"
+		sizeLimit         = resource.MustParse("1Gi")
+		hostPathDir       = core.HostPathDirectory
+		cachingMode       = core.AzureDataDiskCachingReadOnly
+		diskKind          = core.AzureManagedDisk
+		fsMode            = core.PersistentVolumeFilesystem
+	)
+
+	cases := []struct {
+		name   string
+		source core.VolumeSource
+		want   []string
+	}{
+		{
+			name:   "HostPath",
+			source: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/data", Type: &hostPathDir}},
+			want:   []string{"Type:\tHostPath", "Path:\t/data", "HostPathType:\tDirectory"},
+		},
+		{
+			name:   "HostPath without type",
+			source: core.VolumeSource{HostPath: &core.HostPathVolumeSource{Path: "/data"}},
+			want:   []string{"HostPathType:\t<none>"},
+		},
+		{
+			name:   "EmptyDir",
+			source: core.VolumeSource{EmptyDir: &core.EmptyDirVolumeSource{Medium: core.StorageMediumMemory, SizeLimit: &sizeLimit}},
+			want:   []string{"Type:\tEmptyDir", "Medium:\tMemory", "SizeLimit:\t1Gi"},
+		},
+		{
+			name:   "GCEPersistentDisk",
+			source: core.VolumeSource{GCEPersistentDisk: &core.GCEPersistentDiskVolumeSource{PDName: "pd", FSType: "ext4", Partition: 1}},
+			want:   []string{"Type:\tGCEPersistentDisk", "PDName:\tpd", "Partition:\t1"},
+		},
+		{
+			name:   "AWSElasticBlockStore",
+			source: core.VolumeSource{AWSElasticBlockStore: &core.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1"}},
+			want:   []string{"Type:\tAWSElasticBlockStore", "VolumeID:\tvol-1"},
+		},
+		{
+			name:   "GitRepo",
+			source: core.VolumeSource{GitRepo: &core.GitRepoVolumeSource{Repository: "https://example.com/repo.git", Revision: "main"}},
+			want:   []string{"Type:\tGitRepo", "Repository:\thttps://example.com/repo.git", "Revision:\tmain"},
+		},
+		{
+			name:   "Secret",
+			source: core.VolumeSource{Secret: &core.SecretVolumeSource{SecretName: "init-script"}},
+			want:   []string{"Type:\tSecret", "SecretName:\tinit-script", "Optional:\tfalse"},
+		},
+		{
+			name:   "NFS",
+			source: core.VolumeSource{NFS: &core.NFSVolumeSource{Server: "nfs.local", Path: "/exports"}},
+			want:   []string{"Type:\tNFS", "Server:\tnfs.local", "Path:\t/exports"},
+		},
+		{
+			name:   "ISCSI",
+			source: core.VolumeSource{ISCSI: &core.ISCSIVolumeSource{TargetPortal: "10.0.0.1:3260", IQN: "iqn", Portals: []string{"10.0.0.2:3260"}}},
+			want:   []string{"Type:\tISCSI", "TargetPortal:\t10.0.0.1:3260", "Portals:\t10.0.0.2:3260", "SecretRef:\t<none>", "InitiatorName:\t<none>"},
+		},
+		{
+			name:   "Glusterfs",
+			source: core.VolumeSource{Glusterfs: &core.GlusterfsVolumeSource{EndpointsName: "glusterfs", Path: "vol"}},
+			want:   []string{"Type:\tGlusterfs", "EndpointsName:\tglusterfs"},
+		},
+		{
+			name:   "PersistentVolumeClaim",
+			source: core.VolumeSource{PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: "scripts", ReadOnly: true}},
+			want:   []string{"Type:\tPersistentVolumeClaim", "ClaimName:\tscripts", "ReadOnly:\ttrue"},
+		},
+		{
+			name:   "RBD",
+			source: core.VolumeSource{RBD: &core.RBDVolumeSource{CephMonitors: []string{"a", "b"}, RBDImage: "img", SecretRef: &core.LocalObjectReference{Name: "ceph"}}},
+			want:   []string{"Type:\tRBD", "CephMonitors:\ta, b", "SecretRef:\tceph"},
+		},
+		{
+			name:   "FlexVolume",
+			source: core.VolumeSource{FlexVolume: &core.FlexVolumeSource{Driver: "example/flex", Options: map[string]string{"b": "2", "a": "1"}}},
+			want:   []string{"Type:\tFlexVolume", "Driver:\texample/flex", "Options:\ta=1,b=2"},
+		},
+		{
+			name:   "Cinder",
+			source: core.VolumeSource{Cinder: &core.CinderVolumeSource{VolumeID: "cinder-1", FSType: "xfs", ReadOnly: true, SecretRef: &core.LocalObjectReference{Name: "openstack"}}},
+			want:   []string{"Type:\tCinder", "VolumeID:\tcinder-1", "FSType:\txfs", "ReadOnly:\ttrue", "SecretRef:\topenstack"},
+		},
+		{
+			name:   "CephFS",
+			source: core.VolumeSource{CephFS: &core.CephFSVolumeSource{Monitors: []string{"mon"}, User: "admin"}},
+			want:   []string{"Type:\tCephFS", "Monitors:\tmon", "User:\tadmin"},
+		},
+		{
+			name:   "Flocker",
+			source: core.VolumeSource{Flocker: &core.FlockerVolumeSource{DatasetName: "dataset"}},
+			want:   []string{"Type:\tFlocker", "DatasetName:\tdataset"},
+		},
+		{
+			name: "DownwardAPI",
+			source: core.VolumeSource{DownwardAPI: &core.DownwardAPIVolumeSource{Items: []core.DownwardAPIVolumeFile{
+				{Path: "labels", FieldRef: &core.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+				{Path: "cpu", ResourceFieldRef: &core.ResourceFieldSelector{Resource: "limits.cpu"}},
+			}}},
+			want: []string{"Type:\tDownwardAPI", "metadata.labels -> labels", "limits.cpu -> cpu"},
+		},
+		{
+			name:   "FC",
+			source: core.VolumeSource{FC: &core.FCVolumeSource{TargetWWNs: []string{"wwn"}, Lun: &lun}},
+			want:   []string{"Type:\tFC", "TargetWWNs:\twwn", "LUN:\t3"},
+		},
+		{
+			name:   "FC without lun",
+			source: core.VolumeSource{FC: &core.FCVolumeSource{WWIDs: []string{"wwid"}}},
+			want:   []string{"LUN:\t<none>", "WWIDs:\twwid"},
+		},
+		{
+			name:   "AzureFile",
+			source: core.VolumeSource{AzureFile: &core.AzureFileVolumeSource{SecretName: "azure", ShareName: "share"}},
+			want:   []string{"Type:\tAzureFile", "SecretName:\tazure", "ShareName:\tshare"},
+		},
+		{
+			name:   "ConfigMap",
+			source: core.VolumeSource{ConfigMap: &core.ConfigMapVolumeSource{LocalObjectReference: core.LocalObjectReference{Name: "scripts"}, Optional: &readOnly}},
+			want:   []string{"Type:\tConfigMap", "Name:\tscripts", "Optional:\ttrue"},
+		},
+		{
+			name:   "VsphereVolume",
+			source: core.VolumeSource{VsphereVolume: &core.VsphereVirtualDiskVolumeSource{VolumePath: "[ds] vol.vmdk"}},
+			want:   []string{"Type:\tvSphereVolume", "VolumePath:\t[ds] vol.vmdk"},
+		},
+		{
+			name:   "Quobyte",
+			source: core.VolumeSource{Quobyte: &core.QuobyteVolumeSource{Registry: "registry:7861", Volume: "vol"}},
+			want:   []string{"Type:\tQuobyte", "Registry:\tregistry:7861", "Volume:\tvol"},
+		},
+		{
+			name: "AzureDisk",
+			source: core.VolumeSource{AzureDisk: &core.AzureDiskVolumeSource{
+				DiskName:    "disk",
+				DataDiskURI: "https://example.blob.core.windows.net/vhds/disk.vhd",
+				CachingMode: &cachingMode,
+				FSType:      &fsType,
+				ReadOnly:    &readOnly,
+				Kind:        &diskKind,
+			}},
+			want: []string{"Type:\tAzureDisk", "DiskName:\tdisk", "Kind:\tManaged", "FSType:\text4", "CachingMode:\tReadOnly", "ReadOnly:\ttrue"},
+		},
+		{
+			name:   "AzureDisk without optional fields",
+			source: core.VolumeSource{AzureDisk: &core.AzureDiskVolumeSource{DiskName: "disk"}},
+			want:   []string{"Kind:\t<none>", "FSType:\t<none>", "CachingMode:\t<none>", "ReadOnly:\tfalse"},
+		},
+		{
+			name:   "PhotonPersistentDisk",
+			source: core.VolumeSource{PhotonPersistentDisk: &core.PhotonPersistentDiskVolumeSource{PdID: "photon"}},
+			want:   []string{"Type:\tPhotonPersistentDisk", "PdID:\tphoton"},
+		},
+		{
+			name: "Projected",
+			source: core.VolumeSource{Projected: &core.ProjectedVolumeSource{Sources: []core.VolumeProjection{
+				{Secret: &core.SecretProjection{LocalObjectReference: core.LocalObjectReference{Name: "auth"}}},
+				{ConfigMap: &core.ConfigMapProjection{LocalObjectReference: core.LocalObjectReference{Name: "scripts"}, Optional: &readOnly}},
+				{DownwardAPI: &core.DownwardAPIProjection{Items: []core.DownwardAPIVolumeFile{
+					{Path: "name", FieldRef: &core.ObjectFieldSelector{FieldPath: "metadata.name"}},
+				}}},
+				{ServiceAccountToken: &core.ServiceAccountTokenProjection{Audience: "vault", ExpirationSeconds: &expiration, Path: "token"}},
+			}}},
+			want: []string{
+				"Type:\tProjected",
+				"SecretName:\tauth",
+				"ConfigMapName:\tscripts",
+				"ConfigMapOptional:\ttrue",
+				"metadata.name -> name",
+				"TokenExpirationSeconds:\t3600",
+				"TokenAudience:\tvault",
+				"TokenPath:\ttoken",
+			},
+		},
+		{
+			name:   "Projected without sources",
+			source: core.VolumeSource{Projected: &core.ProjectedVolumeSource{}},
+			want:   []string{"Sources:\t<none>"},
+		},
+		{
+			name:   "PortworxVolume",
+			source: core.VolumeSource{PortworxVolume: &core.PortworxVolumeSource{VolumeID: "pwx"}},
+			want:   []string{"Type:\tPortworxVolume", "VolumeID:\tpwx"},
+		},
+		{
+			name:   "ScaleIO",
+			source: core.VolumeSource{ScaleIO: &core.ScaleIOVolumeSource{Gateway: "https://gateway", System: "sio", VolumeName: "vol"}},
+			want:   []string{"Type:\tScaleIO", "Gateway:\thttps://gateway", "System:\tsio", "SecretRef:\t<none>"},
+		},
+		{
+			name:   "StorageOS",
+			source: core.VolumeSource{StorageOS: &core.StorageOSVolumeSource{VolumeName: "vol", VolumeNamespace: "ns"}},
+			want:   []string{"Type:\tStorageOS", "VolumeName:\tvol", "VolumeNamespace:\tns"},
+		},
+		{
+			name: "CSI",
+			source: core.VolumeSource{CSI: &core.CSIVolumeSource{
+				Driver:               "secrets-store.csi.k8s.io",
+				ReadOnly:             &readOnly,
+				VolumeAttributes:     map[string]string{"secretProviderClass": "vault"},
+				NodePublishSecretRef: &core.LocalObjectReference{Name: "creds"},
+			}},
+			want: []string{"Type:\tCSI", "Driver:\tsecrets-store.csi.k8s.io", "FSType:\t<none>", "ReadOnly:\ttrue", "NodePublishSecretRef:\tcreds", "VolumeAttributes:\tsecretProviderClass=vault"},
+		},
+		{
+			name: "Ephemeral",
+			source: core.VolumeSource{Ephemeral: &core.EphemeralVolumeSource{VolumeClaimTemplate: &core.PersistentVolumeClaimTemplate{
+				Spec: core.PersistentVolumeClaimSpec{
+					StorageClassName: &storageName,
+					AccessModes:      []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+					VolumeMode:       &fsMode,
+					Resources: core.ResourceRequirements{
+						Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}}},
+			want: []string{"Type:\tEphemeralVolume", "StorageClass:\tstandard", "Capacity:\t1Gi", "Access Modes:\tRWO", "VolumeMode:\tFilesystem"},
+		},
+		{
+			name:   "Ephemeral without template",
+			source: core.VolumeSource{Ephemeral: &core.EphemeralVolumeSource{}},
+			want:   []string{"Type:\tEphemeralVolume", "VolumeClaimTemplate:\t<none>"},
+		},
+		{
+			name:   "unknown",
+			source: core.VolumeSource{},
+			want:   []string{"<unknown>"},
+		},
+	}
+
+	covered := map[string]bool{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			describeVolume(LEVEL_0, c.source, describe.NewPrefixWriter(buf))
+			out := buf.String()
+
+			if !strings.HasPrefix(out, "Volume:\n") {
+				t.Errorf("expected output to start with Volume:, got:\n%s", out)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out)
+				}
+			}
+		})
+
+		v := reflect.ValueOf(c.source)
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).IsNil() {
+				covered[v.Type().Field(i).Name] = true
+			}
+		}
+	}
+
+	typ := reflect.TypeOf(core.VolumeSource{})
+	for i := 0; i < typ.NumField(); i++ {
+		if name := typ.Field(i).Name; !covered[name] {
+			t.Errorf("no test case for volume source %s", name)
+		}
+	}
+}